@@ -0,0 +1,251 @@
+package letterboxd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// ActivityItem is a single entry from a users activity feed
+type ActivityItem struct {
+	ID          string     `json:"id"`
+	Description string     `json:"description"`
+	Username    string     `json:"username,omitempty"` // Member the activity is about
+	Action      string     `json:"action,omitempty"`   // Example: 'watched', 'rated' or 'added'
+	FilmSlug    string     `json:"film_slug,omitempty"`
+	Timestamp   string     `json:"timestamp,omitempty"`
+	Time        *time.Time `json:"time,omitempty"` // Parsed from Timestamp, or the relative time shown. nil if there is neither
+}
+
+// relativeTimeRegex matches relative times, like '2 days ago' or 'an hour ago'
+var relativeTimeRegex = regexp.MustCompile(`^(\d+|an?|one)\s+(second|minute|hour|day|week|month|year)s?\s+ago$`)
+
+// watchActions are the activity actions that mean the member watched the film
+var watchActions = []string{"watched", "rewatched", "rated", "reviewed"}
+
+// ActivityPage is a single 'load more' chunk of an activity feed. Next is the
+// cursor URL for the following chunk, and is empty when the feed is exhausted
+type ActivityPage struct {
+	Items []*ActivityItem
+	Next  string
+}
+
+// ExtractActivity returns an ActivityPage from an io.Reader
+func ExtractActivity(r io.Reader) (interface{}, *Pagination, error) {
+	doc, err := newDocumentFromReader(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	page := &ActivityPage{
+		Items: []*ActivityItem{},
+	}
+	doc.Find("section.activity-row").Each(func(i int, s *goquery.Selection) {
+		item := &ActivityItem{
+			ID:          s.AttrOr("data-activity-id", ""),
+			Description: strings.Join(strings.Fields(s.Find("div.table-activity-description").Text()), " "),
+			Timestamp:   stringOr(s.Find("time").AttrOr("datetime", ""), s.Find("[data-time]").AttrOr("data-time", "")),
+		}
+		item.Time = activityTimeWithSelection(s, item.Timestamp, time.Now())
+		name := s.Find("div.table-activity-description a.name").First()
+		item.Username = strings.Trim(name.AttrOr("href", ""), "/")
+		if nameText := strings.Join(strings.Fields(name.Text()), " "); nameText != "" {
+			if fields := strings.Fields(strings.TrimPrefix(item.Description, nameText)); len(fields) > 0 {
+				item.Action = fields[0]
+			}
+		}
+		s.Find("a.target").EachWithBreak(func(i int, s *goquery.Selection) bool {
+			parts := strings.Split(strings.Trim(s.AttrOr("href", ""), "/"), "/")
+			if len(parts) == 3 && parts[1] == "film" {
+				item.FilmSlug = parts[2]
+			}
+			return false
+		})
+		page.Items = append(page.Items, item)
+	})
+	page.Next = doc.Find("[data-infinite-scroll]").First().AttrOr("data-infinite-scroll", "")
+	return page, nil, nil
+}
+
+// activityTimeWithSelection returns when an activity happened. The machine
+// readable timestamp is preferred, falling back on the text shown, which can
+// be relative to now, like '2 days ago'. Returns nil if neither can be parsed
+func activityTimeWithSelection(s *goquery.Selection, timestamp string, now time.Time) *time.Time {
+	if t := absoluteActivityTime(timestamp); t != nil {
+		return t
+	}
+	text := strings.TrimSpace(s.Find("time").First().Text())
+	if text == "" {
+		text = strings.TrimSpace(s.Find("[data-time]").First().Text())
+	}
+	if t, err := time.Parse("02 Jan 2006", text); err == nil {
+		return &t
+	}
+	return relativeActivityTime(text, now)
+}
+
+// absoluteActivityTime parses a datetime or data-time attribute, which is
+// either RFC 3339 or a unix timestamp in seconds or milliseconds
+func absoluteActivityTime(val string) *time.Time {
+	if val == "" {
+		return nil
+	}
+	if t, err := time.Parse(time.RFC3339, val); err == nil {
+		return &t
+	}
+	i, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return nil
+	}
+	// Anything this big is past the year 33658 in seconds, so it's milliseconds
+	if i > 1e12 {
+		t := time.UnixMilli(i).UTC()
+		return &t
+	}
+	t := time.Unix(i, 0).UTC()
+	return &t
+}
+
+// relativeActivityTime returns the time that text like '2 days ago' refers
+// to, counting back from now. Months and years are calendar months and years
+func relativeActivityTime(text string, now time.Time) *time.Time {
+	text = strings.ToLower(strings.Join(strings.Fields(text), " "))
+	switch text {
+	case "":
+		return nil
+	case "just now", "now":
+		return &now
+	case "yesterday":
+		t := now.AddDate(0, 0, -1)
+		return &t
+	}
+	m := relativeTimeRegex.FindStringSubmatch(text)
+	if m == nil {
+		return nil
+	}
+	n := 1
+	if i, err := strconv.Atoi(m[1]); err == nil {
+		n = i
+	}
+	var t time.Time
+	switch m[2] {
+	case "second":
+		t = now.Add(-time.Duration(n) * time.Second)
+	case "minute":
+		t = now.Add(-time.Duration(n) * time.Minute)
+	case "hour":
+		t = now.Add(-time.Duration(n) * time.Hour)
+	case "day":
+		t = now.AddDate(0, 0, -n)
+	case "week":
+		t = now.AddDate(0, 0, -7*n)
+	case "month":
+		t = now.AddDate(0, -n, 0)
+	case "year":
+		t = now.AddDate(-n, 0, 0)
+	}
+	return &t
+}
+
+func (u *UserServiceOp) activityPageWithURL(ctx context.Context, url string) (*ActivityPage, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	pData, resp, err := u.client.sendRequest(req, ExtractActivity)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Response != nil {
+		dclose(resp.Body)
+	}
+	page, ok := pData.Data.(*ActivityPage)
+	if !ok {
+		return nil, errors.New("unexpected data type for activity page")
+	}
+	return page, nil
+}
+
+// StreamActivity streams a users activity feed in to the given channels.
+// Unlike the numbered pages used elsewhere, activity is loaded with a 'load
+// more' cursor, which is followed until exhausted, or until maxCursorPages
+// have been fetched
+func (u *UserServiceOp) StreamActivity(ctx context.Context, username string, rchan chan *ActivityItem, done chan error) {
+	username, err := normalizeUsername(username)
+	if err != nil {
+		done <- err
+		return
+	}
+	done <- u.streamActivityWithURL(ctx, fmt.Sprintf("%s/ajax/activity-pagination/%s/", u.client.baseURL, username), rchan, nil)
+}
+
+// FollowingWatched streams what the people a user follows have recently
+// watched, from the following activity feed. Only watches, rewatches,
+// ratings and reviews are sent, so watchlist additions, likes and follows are
+// left out
+func (u *UserServiceOp) FollowingWatched(ctx context.Context, username string, rchan chan *ActivityItem, done chan error) {
+	username, err := normalizeUsername(username)
+	if err != nil {
+		done <- err
+		return
+	}
+	done <- u.streamActivityWithURL(ctx, fmt.Sprintf("%s/ajax/activity-pagination/%s/following/", u.client.baseURL, username), rchan, func(item *ActivityItem) bool {
+		return item.FilmSlug != "" && stringInSlice(item.Action, watchActions)
+	})
+}
+
+// streamActivityWithURL follows an activity feed cursor starting at url,
+// sending the items that keep returns true for. A nil keep sends everything
+func (u *UserServiceOp) streamActivityWithURL(ctx context.Context, url string, rchan chan *ActivityItem, keep func(*ActivityItem) bool) error {
+	for i := 0; i < maxCursorPages && url != ""; i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		page, err := u.activityPageWithURL(ctx, url)
+		if err != nil {
+			return err
+		}
+		for _, item := range page.Items {
+			if keep != nil && !keep(item) {
+				continue
+			}
+			select {
+			case rchan <- item:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		switch {
+		case page.Next == "":
+			url = ""
+		case strings.HasPrefix(page.Next, "http"):
+			url = page.Next
+		default:
+			url = fmt.Sprintf("%s%s", u.client.baseURL, page.Next)
+		}
+	}
+	return nil
+}
+
+// SlurpActivity is just a helper to quickly read in all Activity streams
+func SlurpActivity(itemC chan *ActivityItem, doneC chan error) ([]*ActivityItem, error) {
+	var ret []*ActivityItem
+	for {
+		select {
+		case item := <-itemC:
+			ret = append(ret, item)
+		case err := <-doneC:
+			if err != nil {
+				return nil, err
+			}
+			return ret, nil
+		}
+	}
+}
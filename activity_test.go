@@ -0,0 +1,117 @@
+package letterboxd
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractActivity(t *testing.T) {
+	f, err := os.Open("testdata/user/activity/1.html")
+	require.NoError(t, err)
+	defer f.Close()
+	items, pagination, err := ExtractActivity(f)
+	require.NoError(t, err)
+	require.Nil(t, pagination)
+	page := items.(*ActivityPage)
+	require.Equal(t, 2, len(page.Items))
+	require.Equal(t, "9001", page.Items[0].ID)
+	require.Equal(t, "parasite-2019", page.Items[0].FilmSlug)
+	require.Equal(t, "Some Guy watched Parasite", page.Items[0].Description)
+	require.Equal(t, "someguy", page.Items[0].Username)
+	require.Equal(t, "watched", page.Items[0].Action)
+	require.Equal(t, "added", page.Items[1].Action)
+	require.Equal(t, "2023-01-02T15:04:05.000Z", page.Items[0].Timestamp)
+	require.Equal(t, time.Date(2023, time.January, 2, 15, 4, 5, 0, time.UTC), *page.Items[0].Time)
+	require.Equal(t, "/ajax/activity-pagination/someguy/?after=9000", page.Next)
+}
+
+func TestStreamActivity(t *testing.T) {
+	itemC := make(chan *ActivityItem)
+	doneC := make(chan error)
+	go sc.User.StreamActivity(context.TODO(), "someguy", itemC, doneC)
+	items, err := SlurpActivity(itemC, doneC)
+	require.NoError(t, err)
+	require.Equal(t, 3, len(items))
+	require.Equal(t, "8999", items[2].ID)
+}
+
+func TestStreamActivityCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	itemC := make(chan *ActivityItem)
+	doneC := make(chan error)
+	go sc.User.StreamActivity(ctx, "someguy", itemC, doneC)
+	_, err := SlurpActivity(itemC, doneC)
+	require.Error(t, err)
+}
+
+func TestStreamActivityStopsReading(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	itemC := make(chan *ActivityItem)
+	doneC := make(chan error)
+	go sc.User.StreamActivity(ctx, "someguy", itemC, doneC)
+	// Take one item, then walk away without reading any more
+	<-itemC
+	cancel()
+	select {
+	case err := <-doneC:
+		require.ErrorIs(t, err, context.Canceled)
+	case <-time.After(5 * time.Second):
+		t.Fatal("activity stream did not stop after cancel")
+	}
+}
+
+func TestFollowingWatched(t *testing.T) {
+	itemC := make(chan *ActivityItem)
+	doneC := make(chan error)
+	go sc.User.FollowingWatched(context.TODO(), "someguy", itemC, doneC)
+	items, err := SlurpActivity(itemC, doneC)
+	require.NoError(t, err)
+	// The watchlist add, like and follow are left out
+	require.Equal(t, 3, len(items))
+	require.Equal(t, "karsten", items[0].Username)
+	require.Equal(t, "watched", items[0].Action)
+	require.Equal(t, "cure", items[0].FilmSlug)
+	require.Equal(t, "rated", items[1].Action)
+	require.Equal(t, "parasite-2019", items[1].FilmSlug)
+	require.Equal(t, "jay", items[2].Username)
+	require.Equal(t, "rewatched", items[2].Action)
+}
+
+func TestActivityTime(t *testing.T) {
+	now := time.Date(2023, time.March, 10, 12, 0, 0, 0, time.UTC)
+	tests := map[string]struct {
+		html string
+		want *time.Time
+	}{
+		"datetime":              {html: `<time datetime="2023-01-02T15:04:05Z">2 days ago</time>`, want: timePtr(time.Date(2023, time.January, 2, 15, 4, 5, 0, time.UTC))},
+		"data-time-seconds":     {html: `<span data-time="1672671845">2 days ago</span>`, want: timePtr(time.Date(2023, time.January, 2, 15, 4, 5, 0, time.UTC))},
+		"data-time-millis":      {html: `<span data-time="1672671845000"></span>`, want: timePtr(time.Date(2023, time.January, 2, 15, 4, 5, 0, time.UTC))},
+		"date-text":             {html: `<time>02 Jan 2023</time>`, want: timePtr(time.Date(2023, time.January, 2, 0, 0, 0, 0, time.UTC))},
+		"relative-days":         {html: `<time>2 days ago</time>`, want: timePtr(time.Date(2023, time.March, 8, 12, 0, 0, 0, time.UTC))},
+		"relative-an-hour":      {html: `<time>an hour ago</time>`, want: timePtr(time.Date(2023, time.March, 10, 11, 0, 0, 0, time.UTC))},
+		"relative-weeks":        {html: `<time> 3  weeks ago </time>`, want: timePtr(time.Date(2023, time.February, 17, 12, 0, 0, 0, time.UTC))},
+		"relative-month":        {html: `<time>1 month ago</time>`, want: timePtr(time.Date(2023, time.February, 10, 12, 0, 0, 0, time.UTC))},
+		"just-now":              {html: `<time>just now</time>`, want: timePtr(now)},
+		"bad-datetime-relative": {html: `<time datetime="yesterday-ish">5 minutes ago</time>`, want: timePtr(time.Date(2023, time.March, 10, 11, 55, 0, 0, time.UTC))},
+		"missing":               {html: `<p>Some Guy watched Parasite</p>`, want: nil},
+		"unparseable":           {html: `<time>a while back</time>`, want: nil},
+	}
+	for name, tt := range tests {
+		doc, err := goquery.NewDocumentFromReader(strings.NewReader(`<section class="activity-row">` + tt.html + `</section>`))
+		require.NoError(t, err, name)
+		s := doc.Find("section.activity-row")
+		timestamp := stringOr(s.Find("time").AttrOr("datetime", ""), s.Find("[data-time]").AttrOr("data-time", ""))
+		require.Equal(t, tt.want, activityTimeWithSelection(s, timestamp, now), name)
+	}
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
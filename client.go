@@ -11,7 +11,9 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
+	"strings"
 	"sync"
 	"time"
 
@@ -23,25 +25,69 @@ const (
 	baseURL   = "https://letterboxd.com"
 	maxPages  = 50
 	userAgent = "letterrestd"
+	// maxCursorPages is the most 'load more' cursors that will be followed
+	maxCursorPages = 50
+	// assetBaseURL is the Letterboxd CDN, where posters, backdrops and
+	// avatars are served from
+	assetBaseURL = "https://a.ltrbxd.com"
+	// maxResponseSize is the largest page that will be read, in bytes. The
+	// biggest Letterboxd pages are a few hundred KB
+	maxResponseSize = 32 << 20
 )
 
+// ErrNotFound is returned when Letterboxd responds with a 404
+var ErrNotFound = errors.New("that entry was not found, are you sure it exists?")
+
+// ErrResponseTooLarge is returned when a response is bigger than the limit set
+// with WithMaxResponseSize
+var ErrResponseTooLarge = errors.New("response is too large")
+
+// ErrStrictExtraction is returned when using WithStrictExtraction, and part of
+// a page that should be there could not be found
+var ErrStrictExtraction = errors.New("strict extraction failed")
+
+// ErrInvalidProxy is returned from every request when the client was set up
+// with a WithProxy that could not be used
+var ErrInvalidProxy = errors.New("invalid proxy")
+
+// extractionWarner is implemented by extracted data that keeps track of the
+// expected parts of a page that were missing
+type extractionWarner interface {
+	extractionWarnings() []string
+}
+
 // Client represents the thing containing services and methods for interacting with Letterboxd
 type Client struct {
 	client    *http.Client
 	UserAgent string
 	// Config    ClientConfig
 	baseURL string
+	// assetBaseURL is what relative image URLs are resolved against
+	assetBaseURL string
 	// Options
 	MaxConcurrentPages int
 	Cache              *cache.Cache
-
-	User UserService
-	Film FilmService
-	List ListService
-	URL  URLService
+	keepRawHTML        bool
+	defaultHeaders     http.Header
+	strictExtraction   bool
+	filmJSON           bool
+	maxReviews         int
+	maxResponseSize    int64
+	cacheTime          *time.Duration
+	warnOut            io.Writer
+	proxyURL           *url.URL
+	// proxyErr is why WithProxy could not be used, returned from every request
+	proxyErr error
+
+	User   UserService
+	Film   FilmService
+	List   ListService
+	URL    URLService
+	Person PersonService
+	Review ReviewService
 }
 
-// ClientConfig is the configuration strcut for the client
+// ClientConfig is the configuration strcut for the client, see NewWithConfig
 type ClientConfig struct {
 	HTTPClient         *http.Client
 	BaseURL            string
@@ -60,6 +106,9 @@ type ClientConfig struct {
 type Response struct {
 	*http.Response
 	FromCache bool
+	// RawBody is the page that the results were extracted from. Only set when
+	// using WithKeepRawHTML, and the page did not come from the cache
+	RawBody []byte
 	// pagination *Pagination
 }
 
@@ -70,6 +119,21 @@ func WithCache(cc *cache.Cache) func(*Client) {
 	}
 }
 
+// WithCacheTime sets how long pages and films are kept in the cache. By
+// default pages are kept for a day, and films for a week
+func WithCacheTime(d time.Duration) func(*Client) {
+	return func(c *Client) {
+		c.cacheTime = &d
+	}
+}
+
+// WithHTTPClient sets the http.Client used for all requests
+func WithHTTPClient(hc *http.Client) func(*Client) {
+	return func(c *Client) {
+		c.client = hc
+	}
+}
+
 // WithNoCache removes the default cache
 func WithNoCache() func(*Client) {
 	return func(c *Client) {
@@ -77,10 +141,132 @@ func WithNoCache() func(*Client) {
 	}
 }
 
-// WithBaseURL sets the url (Example: https://letterboxd.com) to use for scraping
+// WithBaseURL sets the url (Example: https://letterboxd.com) to use for scraping.
+// Any trailing slash is dropped, as paths are joined on to it with their own
 func WithBaseURL(u string) func(*Client) {
 	return func(c *Client) {
-		c.baseURL = u
+		c.baseURL = strings.TrimRight(u, "/")
+	}
+}
+
+// WithAssetBaseURL sets the url (Example: https://a.ltrbxd.com) that relative
+// and protocol-relative image URLs, like posters and avatars, are resolved
+// against. Defaults to the Letterboxd CDN
+func WithAssetBaseURL(u string) func(*Client) {
+	return func(c *Client) {
+		c.assetBaseURL = u
+	}
+}
+
+// WithProxy routes all requests through the given http, https or socks5 proxy
+// (Example: socks5://127.0.0.1:1080). The client transport must be an
+// *http.Transport, which is copied rather than changed. If the proxy can not
+// be used, every request returns an ErrInvalidProxy error
+func WithProxy(proxyURL string) func(*Client) {
+	return func(c *Client) {
+		c.proxyURL, c.proxyErr = nil, nil
+		u, err := url.Parse(proxyURL)
+		if err != nil {
+			c.proxyErr = fmt.Errorf("%w: %v", ErrInvalidProxy, err)
+			return
+		}
+		if !stringInSlice(u.Scheme, []string{"http", "https", "socks5"}) || u.Host == "" {
+			c.proxyErr = fmt.Errorf("%w: %v", ErrInvalidProxy, proxyURL)
+			return
+		}
+		c.proxyURL = u
+	}
+}
+
+// applyProxy points a copy of the http.Client and its transport at the
+// WithProxy URL, once all the options are in, so it works with WithHTTPClient
+// in any order
+func (c *Client) applyProxy() {
+	if c.proxyURL == nil {
+		return
+	}
+	var t *http.Transport
+	switch ct := c.client.Transport.(type) {
+	case nil:
+		t = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		t = ct.Clone()
+	default:
+		c.proxyErr = fmt.Errorf("%w: the http client transport is a %T, not an *http.Transport", ErrInvalidProxy, ct)
+		return
+	}
+	t.Proxy = http.ProxyURL(c.proxyURL)
+	hc := *c.client
+	hc.Transport = t
+	c.client = &hc
+}
+
+// WithDefaultHeaders sets headers, like Accept-Language or Cookie, on every
+// request. Headers already set on a request are left alone, and a User-Agent
+// here takes the place of the client UserAgent
+func WithDefaultHeaders(h http.Header) func(*Client) {
+	return func(c *Client) {
+		c.defaultHeaders = h.Clone()
+	}
+}
+
+// WithStrictExtraction returns an ErrStrictExtraction error when a page is
+// missing something the extractor expected to find, instead of leaving the
+// field empty. Handy for catching changes to the Letterboxd markup. Off by
+// default
+func WithStrictExtraction(strict bool) func(*Client) {
+	return func(c *Client) {
+		c.strictExtraction = strict
+	}
+}
+
+// WithFilmJSON gets films from the Letterboxd film JSON endpoint, falling back
+// to the film page if that fails. The JSON is less likely to break with markup
+// changes, but only has the basics, like the title, year and ID. There are no
+// ExternalIDs, so anything matching films by IMDB or TMDB ID, like
+// GetWatchedIMDBIDs or FilmSet.ByIMDB, finds nothing with this on. Off by
+// default
+func WithFilmJSON(enabled bool) func(*Client) {
+	return func(c *Client) {
+		c.filmJSON = enabled
+	}
+}
+
+// WithMaxReviews sets the most reviews streamed for a single film. Defaults to
+// 500, as popular films can have many thousands of reviews. n less than 1
+// keeps the default
+func WithMaxReviews(n int) func(*Client) {
+	return func(c *Client) {
+		if n > 0 {
+			c.maxReviews = n
+		}
+	}
+}
+
+// WithWarnWriter sets where warnings that are not worth failing over, like a
+// film with no year, are written. Defaults to stderr, and nil throws them away
+func WithWarnWriter(w io.Writer) func(*Client) {
+	return func(c *Client) {
+		if w == nil {
+			w = io.Discard
+		}
+		c.warnOut = w
+	}
+}
+
+// WithMaxResponseSize sets the largest response body that will be read, in
+// bytes. Defaults to 32MB, which no real page gets close to
+func WithMaxResponseSize(n int64) func(*Client) {
+	return func(c *Client) {
+		c.maxResponseSize = n
+	}
+}
+
+// WithKeepRawHTML stores the raw page on the Response, which is handy for
+// debugging bad extractions. Off by default to save memory
+func WithKeepRawHTML(keep bool) func(*Client) {
+	return func(c *Client) {
+		c.keepRawHTML = keep
 	}
 }
 
@@ -97,8 +283,12 @@ func New(options ...func(*Client)) *Client {
 			},
 		},
 		UserAgent:          userAgent,
+		warnOut:            os.Stderr,
 		baseURL:            baseURL,
+		assetBaseURL:       assetBaseURL,
 		MaxConcurrentPages: maxPages,
+		maxReviews:         maxReviews,
+		maxResponseSize:    maxResponseSize,
 		Cache: cache.New(&cache.Options{
 			Redis: redis.NewClient(&redis.Options{
 				Addr: "127.0.0.1:6379",
@@ -111,14 +301,73 @@ func New(options ...func(*Client)) *Client {
 	for _, o := range options {
 		o(c)
 	}
+	c.applyProxy()
 
 	c.User = &UserServiceOp{client: c}
 	c.Film = &FilmServiceOp{client: c}
 	c.URL = &URLServiceOp{client: c}
 	c.List = &ListServiceOp{client: c}
+	c.Person = &PersonServiceOp{client: c}
+	c.Review = &ReviewServiceOp{client: c}
 	return c
 }
 
+// NewWithConfig returns a new client using a ClientConfig, instead of
+// functional options. Each field is mapped to the equivalent option, and zero
+// values keep the New defaults. DisableCache wins over any of the cache
+// settings, then Cache, then RedisClient, then RedisHost
+func NewWithConfig(config ClientConfig) *Client {
+	return New(optionsWithConfig(config)...)
+}
+
+// optionsWithConfig returns the functional options for a ClientConfig
+func optionsWithConfig(config ClientConfig) []func(*Client) {
+	var opts []func(*Client)
+	if config.HTTPClient != nil {
+		opts = append(opts, WithHTTPClient(config.HTTPClient))
+	}
+	if config.BaseURL != "" {
+		opts = append(opts, WithBaseURL(config.BaseURL))
+	}
+	if config.MaxConcurrentPages > 0 {
+		opts = append(opts, func(c *Client) {
+			c.MaxConcurrentPages = config.MaxConcurrentPages
+		})
+	}
+	if config.CacheTime != nil {
+		opts = append(opts, WithCacheTime(*config.CacheTime))
+	}
+	switch {
+	case config.DisableCache:
+		opts = append(opts, WithNoCache())
+	case config.Cache != nil:
+		opts = append(opts, WithCache(config.Cache))
+	case config.RedisClient != nil || config.RedisHost != "":
+		db := config.RedisClient
+		if db == nil {
+			db = redis.NewClient(&redis.Options{
+				Addr:     config.RedisHost,
+				Password: config.RedisPassword,
+				DB:       config.RedisDB,
+			})
+		}
+		opts = append(opts, WithCache(cache.New(&cache.Options{
+			Redis:      db,
+			LocalCache: cache.NewTinyLFU(1000, time.Minute),
+		})))
+	}
+	return opts
+}
+
+// cacheTTL returns how long to cache something, using d unless changed with
+// WithCacheTime
+func (c *Client) cacheTTL(d time.Duration) time.Duration {
+	if c.cacheTime != nil {
+		return *c.cacheTime
+	}
+	return d
+}
+
 // PageData just provides Pagination info and 'Data'
 type PageData struct {
 	Data       interface{}
@@ -170,7 +419,7 @@ func (c *Client) setCache(ctx context.Context, key string, pData PageData) {
 			Ctx:   ctx,
 			Key:   key,
 			Value: pData,
-			TTL:   time.Hour * 24,
+			TTL:   c.cacheTTL(time.Hour * 24),
 		}); err != nil {
 			fmt.Fprintf(os.Stderr, "Error writing cache: %v", err)
 		}
@@ -191,7 +440,7 @@ func checkResponse(res *http.Response) error {
 		case res.StatusCode == http.StatusTooManyRequests:
 			return fmt.Errorf("too many requests.  Check rate limit and make sure the userAgent is set right")
 		case res.StatusCode == http.StatusNotFound:
-			return fmt.Errorf("that entry was not found, are you sure it exists?")
+			return ErrNotFound
 		default:
 			return fmt.Errorf("error, status code: %d", res.StatusCode)
 		}
@@ -199,55 +448,171 @@ func checkResponse(res *http.Response) error {
 	return nil
 }
 
+// pageCacheKey returns the key a page is cached under. Every page of a
+// resource should be requested with the same URL format, so that they share
+// a key format too
+func pageCacheKey(u *url.URL) string {
+	key := fmt.Sprintf("/letterboxd/fullpage%s", u.Path)
+	// Cursor based pages only differ by their query
+	if u.RawQuery != "" {
+		key = fmt.Sprintf("%s?%s", key, u.RawQuery)
+	}
+	return key
+}
+
+// validatedPage is a previously fetched page body, along with the validators
+// needed to ask Letterboxd if it has changed since
+type validatedPage struct {
+	ETag         string
+	LastModified string
+	Body         []byte
+}
+
+func (c *Client) getValidatedPage(ctx context.Context, key string) *validatedPage {
+	if c.Cache == nil {
+		return nil
+	}
+	vp := &validatedPage{}
+	if err := c.Cache.Get(ctx, key, vp); err != nil {
+		return nil
+	}
+	return vp
+}
+
+func (c *Client) setValidatedPage(ctx context.Context, key string, vp validatedPage) {
+	if c.Cache != nil {
+		if err := c.Cache.Set(&cache.Item{
+			Ctx:   ctx,
+			Key:   key,
+			Value: vp,
+			TTL:   c.cacheTTL(time.Hour * 24 * 7),
+		}); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing cache: %v", err)
+		}
+	}
+}
+
+// applyValidators makes req a conditional request, using the validators of a
+// previously fetched page
+func applyValidators(req *http.Request, vp *validatedPage) {
+	if vp == nil {
+		return
+	}
+	if vp.ETag != "" {
+		req.Header.Set("If-None-Match", vp.ETag)
+	}
+	if vp.LastModified != "" {
+		req.Header.Set("If-Modified-Since", vp.LastModified)
+	}
+}
+
 func (c *Client) sendRequest(req *http.Request, extractor func(io.Reader) (interface{}, *Pagination, error)) (*PageData, *Response, error) {
-	key := fmt.Sprintf("/letterboxd/fullpage%s", req.URL.Path)
+	if c.proxyErr != nil {
+		return nil, nil, c.proxyErr
+	}
+	key := pageCacheKey(req.URL)
 
 	// Do we have this page cached?
 	pData := c.getFromCache(context.TODO(), key)
 	// Did we get an actual PageData back, or just nil?
-	if pData == nil {
-		res, err := c.client.Do(req)
-		req.Close = true
-		if err != nil {
-			return nil, nil, err
-		}
-		defer dclose(res.Body)
+	if pData != nil {
+		return pData, &Response{
+			FromCache: true,
+		}, nil
+	}
 
-		err = checkResponse(res)
-		if err != nil {
+	// Ask for the page only if it changed since we last fetched it
+	validatedKey := fmt.Sprintf("/letterboxd/validated%s", strings.TrimPrefix(key, "/letterboxd/fullpage"))
+	validated := c.getValidatedPage(context.TODO(), validatedKey)
+	c.applyHeaders(req)
+	applyValidators(req, validated)
+	res, err := c.client.Do(req)
+	req.Close = true
+	if err != nil {
+		return nil, nil, err
+	}
+	defer dclose(res.Body)
+
+	var b []byte
+	fromCache := false
+	if res.StatusCode == http.StatusNotModified && validated != nil {
+		b = validated.Body
+		fromCache = true
+	} else {
+		if err = checkResponse(res); err != nil {
 			return nil, nil, err
 		}
-
-		b, err := io.ReadAll(res.Body)
+		// Read one byte past the limit, to tell a full sized page from a
+		// larger one
+		b, err = io.ReadAll(io.LimitReader(res.Body, c.maxResponseSize+1))
 		if err != nil {
 			return nil, nil, err
 		}
+		if int64(len(b)) > c.maxResponseSize {
+			return nil, nil, fmt.Errorf("%w: more than %d bytes from %v", ErrResponseTooLarge, c.maxResponseSize, req.URL.Path)
+		}
 		if string(b) == "" {
 			fmt.Fprintf(os.Stderr, "got empty body back from: %v", req.URL.String())
 		}
-		items, pagination, err := extractor(bytes.NewReader(b))
-		if err != nil {
-			return nil, nil, err
-		}
-		// log.Warn().Interface("send-pagination", pagination).Send()
-		d := &PageData{
-			Data: items,
+	}
+
+	items, pagination, err := extractor(bytes.NewReader(b))
+	if err != nil {
+		return nil, nil, err
+	}
+	if c.strictExtraction {
+		if w, ok := items.(extractionWarner); ok {
+			if warnings := w.extractionWarnings(); len(warnings) > 0 {
+				return nil, nil, fmt.Errorf("%w for %v: %v", ErrStrictExtraction, req.URL.Path, strings.Join(warnings, "; "))
+			}
 		}
-		if pagination != nil {
-			d.Pagination = *pagination
+	}
+	// log.Warn().Interface("send-pagination", pagination).Send()
+	d := &PageData{
+		Data: items,
+	}
+	if pagination != nil {
+		d.Pagination = *pagination
+	}
+
+	// Save to cache before returning
+	c.setCache(context.TODO(), key, *d)
+	if !fromCache {
+		if etag, lastModified := res.Header.Get("ETag"), res.Header.Get("Last-Modified"); etag != "" || lastModified != "" {
+			c.setValidatedPage(context.TODO(), validatedKey, validatedPage{
+				ETag:         etag,
+				LastModified: lastModified,
+				Body:         b,
+			})
 		}
+	}
 
-		// Save to cache before returning
-		c.setCache(context.TODO(), key, *d)
+	ret := &Response{
+		Response:  res,
+		FromCache: fromCache,
+	}
+	if c.keepRawHTML {
+		ret.RawBody = b
+	}
+	return d, ret, nil
+}
 
-		return d, &Response{
-			Response:  res,
-			FromCache: false,
-		}, nil
+// applyHeaders merges the default headers and User-Agent in to a request,
+// without overriding anything the request already has
+func (c *Client) applyHeaders(req *http.Request) {
+	for k, v := range c.defaultHeaders {
+		if _, ok := req.Header[k]; !ok {
+			req.Header[k] = append([]string(nil), v...)
+		}
+	}
+	if req.Header.Get("User-Agent") == "" && c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
 	}
-	return pData, &Response{
-		FromCache: true,
-	}, nil
+}
+
+// warnf writes a warning that is not worth failing over, on its own line
+func (c *Client) warnf(format string, args ...interface{}) {
+	fmt.Fprintf(c.warnOut, format+"\n", args...)
 }
 
 // ErrorResponse just contains the errors of a response
@@ -286,24 +651,95 @@ func mustParseURL(path string) *url.URL {
 }
 */
 
-func (c *Client) slurpMiddlePages(ctx context.Context, username string, pagination *Pagination, itemsPerFullPage int, rchan chan *Film, listT string) {
+// assetURL returns an image URL made absolute against the asset base URL
+func (c *Client) assetURL(raw string) string {
+	return absoluteURL(c.assetBaseURL, raw)
+}
+
+// gridFilms returns the films on every page of a poster grid in order, without
+// enhancing them. urlForPage returns the URL of a given page
+func (c *Client) gridFilms(ctx context.Context, urlForPage func(int) string) (FilmSet, error) {
+	var films FilmSet
+	for page := 1; ; page++ {
+		pfilms, pagination, err := c.Film.ExtractFilmsWithPath(ctx, urlForPage(page))
+		if err != nil {
+			return nil, err
+		}
+		films = append(films, pfilms...)
+		if pagination.IsLast || page >= pagination.TotalPages {
+			return films, nil
+		}
+	}
+}
+
+// streamPages streams the enhanced films from every page of a poster grid, like
+// a watchlist or a list, in to rchan. urlForPage returns the URL of a given
+// page. The first page seeds the pagination, then the last page is fetched,
+// and the middle pages are fetched concurrently. done gets nil once all pages
+// have been sent, or the error that stopped the stream
+func (c *Client) streamPages(ctx context.Context, urlForPage func(int) string, rchan chan *Film, done chan error) {
+	// Get the first page. This seeds the pagination.
+	firstFilms, pagination, err := c.Film.ExtractEnhancedFilmsWithPath(ctx, urlForPage(1))
+	if err != nil {
+		done <- err
+		return
+	}
+	c.streamPagesFrom(ctx, urlForPage, firstFilms, pagination, rchan, done)
+}
+
+// streamPagesFrom is streamPages for callers that already have the enhanced
+// films and pagination of the first page
+func (c *Client) streamPagesFrom(ctx context.Context, urlForPage func(int) string, firstFilms FilmSet, pagination *Pagination, rchan chan *Film, done chan error) {
+	// Empty grids have nothing more to page through
+	if len(firstFilms) == 0 && pagination.TotalPages <= 1 {
+		done <- ctx.Err()
+		return
+	}
+	for _, film := range firstFilms {
+		if err := sendFilm(ctx, rchan, film); err != nil {
+			done <- err
+			return
+		}
+	}
+
+	var lastCount int
+	// If more than 1 page, get the last page too, which will likely be a
+	// partial batch of films
+	if pagination.TotalPages > 1 {
+		lastFilms, _, err := c.Film.ExtractEnhancedFilmsWithPath(ctx, urlForPage(pagination.TotalPages))
+		if err != nil {
+			done <- err
+			return
+		}
+		lastCount = len(lastFilms)
+		for _, film := range lastFilms {
+			if err := sendFilm(ctx, rchan, film); err != nil {
+				done <- err
+				return
+			}
+		}
+	}
+	pagination.setTotalItemsWithPages(len(firstFilms), lastCount)
+
+	// Gather up the middle pages here
 	if pagination.TotalPages > 2 {
-		pagination.TotalItems += ((pagination.TotalPages - 2) * itemsPerFullPage)
-		middlePageCount := pagination.TotalPages - 2
 		wg := sync.WaitGroup{}
-		wg.Add(middlePageCount)
+		wg.Add(pagination.TotalPages - 2)
 		for i := 2; i < pagination.TotalPages; i++ {
 			go func(i int) {
 				defer wg.Done()
-				pfilms, _, err := c.Film.ExtractEnhancedFilmsWithPath(ctx, fmt.Sprintf("%s/%s/%s/page/%v/", c.baseURL, username, listT, i))
+				pfilms, _, err := c.Film.ExtractEnhancedFilmsWithPath(ctx, urlForPage(i))
 				if err != nil {
 					return
 				}
 				for _, film := range pfilms {
-					rchan <- film
+					if sendFilm(ctx, rchan, film) != nil {
+						return
+					}
 				}
 			}(i)
 		}
 		wg.Wait()
 	}
+	done <- ctx.Err()
 }
@@ -2,6 +2,7 @@ package letterboxd
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -9,6 +10,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -38,14 +40,101 @@ func setup() {
 		case strings.Contains(r.URL.Path, "/dave/list/official-top-250-narrative-feature-films/page/"):
 			pageNo := strings.Split(r.URL.Path, "/")[5]
 			FileToResponseWriter(fmt.Sprintf("testdata/list/lists-page-%v.html", pageNo), w)
+		case strings.HasPrefix(r.URL.Path, "/someguy/list/ranked-notes/"):
+			FileToResponseWriter("testdata/list/ranked-notes.html", w)
+		case r.URL.Path == "/ajax/activity-pagination/someguy/following/":
+			FileToResponseWriter("testdata/user/activity/following.html", w)
+		case r.URL.Path == "/ajax/activity-pagination/someguy/":
+			if r.URL.Query().Get("after") != "" {
+				FileToResponseWriter("testdata/user/activity/2.html", w)
+			} else {
+				FileToResponseWriter("testdata/user/activity/1.html", w)
+			}
+		case r.URL.Path == "/csi/film/cure/stats/":
+			FileToResponseWriter("testdata/film/stats/cure.html", w)
+		case r.URL.Path == "/csi/film/cure/rating-histogram/":
+			FileToResponseWriter("testdata/film/rating-histogram/cure.html", w)
+		case strings.HasPrefix(r.URL.Path, "/films/ajax/by/rating/size/"):
+			FileToResponseWriter("testdata/films/highest-rated.html", w)
 		case strings.HasPrefix(r.URL.Path, "/films/ajax/popular/size/"):
 			FileToResponseWriter("testdata/films/popular.html", w)
+		case strings.HasPrefix(r.URL.Path, "/brokenguy/films"):
+			w.WriteHeader(http.StatusInternalServerError)
+		case strings.HasPrefix(r.URL.Path, "/emptyguy/films"):
+			FileToResponseWriter("testdata/user/films-empty.html", w)
+		case r.URL.Path == "/someguy/films/ratings/":
+			FileToResponseWriter("testdata/user/ratings.html", w)
+		case strings.HasPrefix(r.URL.Path, "/progressguy/watchlist/"):
+			FileToResponseWriter("testdata/user/progress/watchlist.html", w)
+		case strings.HasPrefix(r.URL.Path, "/progressguy/films/"):
+			FileToResponseWriter("testdata/user/progress/films.html", w)
 		case strings.HasPrefix(r.URL.Path, "/singleguy/films"):
 			FileToResponseWriter("testdata/user/films-single.html", w)
+		case strings.HasPrefix(r.URL.Path, "/film/") && strings.HasSuffix(r.URL.Path, "/json/"):
+			// Only sweetback has a JSON fixture, the rest should fall back
+			if r.URL.Path == "/film/sweet-sweetbacks-baadasssss-song/json/" {
+				FileToResponseWriter("testdata/film/json/sweetback.json", w)
+			} else {
+				w.WriteHeader(http.StatusNotFound)
+			}
+		case r.URL.Path == "/film/pulse/releases/":
+			FileToResponseWriter("testdata/film/pulse.html", w)
+		case strings.HasPrefix(r.URL.Path, "/film/nightmare-city"):
+			FileToResponseWriter("testdata/film/missing-year.html", w)
+		case strings.HasPrefix(r.URL.Path, "/film/renamed-old"):
+			w.WriteHeader(http.StatusNotFound)
+		case strings.HasPrefix(r.URL.Path, "/film/renamed-new"):
+			http.Redirect(w, r, "/film/sweet-sweetbacks-baadasssss-song/", http.StatusMovedPermanently)
+			return
+		case strings.HasPrefix(r.URL.Path, "/film/brokenreviews/reviews/"):
+			if strings.Split(r.URL.Path, "/")[7] == "1" {
+				FileToResponseWriter("testdata/film/reviews/1.html", w)
+			} else {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+		case strings.HasPrefix(r.URL.Path, "/film/orderedreviews/reviews/"):
+			pageNo := strings.Split(r.URL.Path, "/")[7]
+			// Page 2 comes back after page 3
+			if pageNo == "2" {
+				time.Sleep(100 * time.Millisecond)
+			}
+			FileToResponseWriter(fmt.Sprintf("testdata/film/reviews-ordered/%v.html", pageNo), w)
+		case strings.HasPrefix(r.URL.Path, "/film/cure/reviews/"):
+			pageNo := strings.Split(r.URL.Path, "/")[7]
+			FileToResponseWriter(fmt.Sprintf("testdata/film/reviews/%v.html", pageNo), w)
+		case strings.HasPrefix(r.URL.Path, "/someguy/likes/lists/page/"):
+			pageNo := strings.Split(r.URL.Path, "/")[5]
+			FileToResponseWriter(fmt.Sprintf("testdata/user/likes-lists/%v.html", pageNo), w)
+		case r.URL.Path == "/film/cure/similar/":
+			FileToResponseWriter("testdata/film/similar/similar.html", w)
+		case strings.HasPrefix(r.URL.Path, "/film/cure/similar/"):
+			FileToResponseWriter(fmt.Sprintf("testdata/film/similar/%v.html", strings.Split(r.URL.Path, "/")[4]), w)
+		case strings.HasPrefix(r.URL.Path, "/film/cure/lists/"):
+			pageNo := strings.Split(r.URL.Path, "/")[7]
+			FileToResponseWriter(fmt.Sprintf("testdata/film/lists/%v.html", pageNo), w)
+		case strings.HasPrefix(r.URL.Path, "/film/cure/members/rated/"):
+			parts := strings.Split(r.URL.Path, "/")
+			fn := fmt.Sprintf("testdata/film/members/rated-%v-%v.html", parts[5], parts[7])
+			if _, err := os.Stat(fn); err != nil {
+				fn = "testdata/film/members/empty.html"
+			}
+			FileToResponseWriter(fn, w)
+		case strings.HasPrefix(r.URL.Path, "/film/age-gated"):
+			if c, err := r.Cookie("letterboxd.adult"); err == nil && c.Value == "true" {
+				FileToResponseWriter("testdata/film/sweetback.html", w)
+			} else {
+				FileToResponseWriter("testdata/film/age-gate.html", w)
+			}
 		case strings.HasPrefix(r.URL.Path, "/film/"):
 			FileToResponseWriter("testdata/film/sweetback.html", w)
+		case strings.Contains(r.URL.Path, "/writer/nicolas-cage"):
+			FileToResponseWriter("testdata/filmography/writer/nicolas-cage.html", w)
 		case strings.Contains(r.URL.Path, "/actor/nicolas-cage"):
 			FileToResponseWriter("testdata/filmography/actor/nicolas-cage.html", w)
+		case strings.Contains(r.URL.Path, "/director/nicolas-cage"):
+			FileToResponseWriter("testdata/filmography/director/nicolas-cage.html", w)
+		case strings.Contains(r.URL.Path, "/producer/nicolas-cage"):
+			FileToResponseWriter("testdata/filmography/producer/nicolas-cage.html", w)
 		case strings.Contains(r.URL.Path, "singleguy/watchlist"):
 			FileToResponseWriter("testdata/user/watchlist-single.html", w)
 		case strings.Contains(r.URL.Path, "/someguy/films/page/"):
@@ -54,15 +143,35 @@ func setup() {
 		case strings.Contains(r.URL.Path, "/someguy/following/page/"):
 			pageNo := strings.Split(r.URL.Path, "/")[4]
 			FileToResponseWriter(fmt.Sprintf("testdata/user/following/%v.html", pageNo), w)
+		case r.URL.Path == "/someguy/followers/":
+			FileToResponseWriter("testdata/user/followers/1.html", w)
 		case strings.Contains(r.URL.Path, "/someguy/followers/page/"):
 			pageNo := strings.Split(r.URL.Path, "/")[4]
 			FileToResponseWriter(fmt.Sprintf("testdata/user/followers/%v.html", pageNo), w)
+		case strings.Contains(r.URL.Path, "/overlapguy/films/diary/"):
+			pageNo := strings.Split(r.URL.Path, "/")[5]
+			FileToResponseWriter(fmt.Sprintf("testdata/user/diary-overlap/%v.html", pageNo), w)
+		case strings.Contains(r.URL.Path, "/reviewguy/films/diary/"):
+			FileToResponseWriter("testdata/user/diary-review-likes.html", w)
+		case r.URL.Path == "/pstinnett/film/cure/":
+			FileToResponseWriter("testdata/user/review/cure.html", w)
 		case strings.Contains(r.URL.Path, "/someguy/films/diary/"):
 			pageNo := strings.Split(r.URL.Path, "/")[5]
 			FileToResponseWriter(fmt.Sprintf("testdata/user/diary-paginated/%v.html", pageNo), w)
-		case strings.Contains(r.URL.Path, "someguy/watchlist/page/"):
+		case strings.Contains(r.URL.Path, "someguy/watchlist/by/release/page/"):
+			FileToResponseWriter("testdata/user/watchlist-by-release.html", w)
+		case strings.Contains(r.URL.Path, "someguy/watchlist/page/"), r.URL.Path == "/someguy/watchlist/":
 			FileToResponseWriter("testdata/user/watchlist.html", w)
 			return
+		case r.URL.Path == "/renamedguy":
+			http.Redirect(w, r, "/dankmccoy/", http.StatusMovedPermanently)
+			return
+		case r.URL.Path == "/dankmccoy" || r.URL.Path == "/dankmccoy/" || r.URL.Path == "/staleguy":
+			FileToResponseWriter("testdata/user/user.html", w)
+		case r.URL.Path == "/noperson":
+			FileToResponseWriter("testdata/user/user-no-person.html", w)
+		case r.URL.Path == "/nostats":
+			FileToResponseWriter("testdata/user/user-no-film-count.html", w)
 		case r.URL.Path == "/someguy":
 			FileToResponseWriter("testdata/user/user.html", w)
 		default:
@@ -111,3 +220,250 @@ func TestNew(t *testing.T) {
 	c := New()
 	require.NotNil(t, c)
 }
+
+func TestWithKeepRawHTML(t *testing.T) {
+	c := New(WithNoCache(), WithBaseURL(srv.URL), WithKeepRawHTML(true))
+	_, resp, err := c.sendRequest(mustNewGetRequest(srv.URL+"/film/sweet-sweetbacks-baadasssss-song"), extractFilmFromFilmPage)
+	require.NoError(t, err)
+	require.NotEmpty(t, resp.RawBody)
+	require.Contains(t, string(resp.RawBody), "Sweet Sweetback")
+
+	// Off by default
+	_, resp, err = sc.sendRequest(mustNewGetRequest(srv.URL+"/film/sweet-sweetbacks-baadasssss-song"), extractFilmFromFilmPage)
+	require.NoError(t, err)
+	require.Nil(t, resp.RawBody)
+}
+
+func TestWithDefaultHeaders(t *testing.T) {
+	var got http.Header
+	hsrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		FileToResponseWriter("testdata/film/sweetback.html", w)
+	}))
+	defer hsrv.Close()
+
+	h := http.Header{}
+	h.Set("Accept-Language", "de-DE")
+	h.Set("Cookie", "letterboxd.signed.in.as=someguy")
+	c := New(WithNoCache(), WithBaseURL(hsrv.URL), WithDefaultHeaders(h))
+	// Changes after the option is applied should not leak in
+	h.Set("Accept-Language", "fr-FR")
+
+	req := mustNewGetRequest(hsrv.URL + "/film/sweet-sweetbacks-baadasssss-song/")
+	req.Header.Set("Cookie", "request-cookie=1")
+	_, _, err := c.sendRequest(req, extractFilmFromFilmPage)
+	require.NoError(t, err)
+	require.Equal(t, "de-DE", got.Get("Accept-Language"))
+	require.Equal(t, "request-cookie=1", got.Get("Cookie"))
+	require.Equal(t, userAgent, got.Get("User-Agent"))
+
+	// User-Agent in the defaults wins over the client one
+	c = New(WithNoCache(), WithBaseURL(hsrv.URL), WithDefaultHeaders(http.Header{"User-Agent": []string{"my-tool/1.0"}}))
+	_, _, err = c.sendRequest(mustNewGetRequest(hsrv.URL+"/film/sweet-sweetbacks-baadasssss-song/"), extractFilmFromFilmPage)
+	require.NoError(t, err)
+	require.Equal(t, "my-tool/1.0", got.Get("User-Agent"))
+}
+
+func TestSendRequestNotModified(t *testing.T) {
+	var fetches, notModified int
+	var gotETag, gotModifiedSince string
+	hsrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		gotETag, gotModifiedSince = r.Header.Get("If-None-Match"), r.Header.Get("If-Modified-Since")
+		if gotETag == `"sweetback-1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"sweetback-1"`)
+		w.Header().Set("Last-Modified", "Wed, 21 Oct 2015 07:28:00 GMT")
+		FileToResponseWriter("testdata/film/sweetback.html", w)
+	}))
+	defer hsrv.Close()
+
+	c := New(WithBaseURL(hsrv.URL), WithCache(cache.New(&cache.Options{
+		LocalCache: cache.NewTinyLFU(1000, time.Minute),
+	})))
+	first, resp, err := c.sendRequest(mustNewGetRequest(hsrv.URL+"/film/sweet-sweetbacks-baadasssss-song/"), extractFilmFromFilmPage)
+	require.NoError(t, err)
+	require.False(t, resp.FromCache)
+	require.Empty(t, gotETag)
+
+	second, resp, err := c.sendRequest(mustNewGetRequest(hsrv.URL+"/film/sweet-sweetbacks-baadasssss-song/"), extractFilmFromFilmPage)
+	require.NoError(t, err)
+	require.True(t, resp.FromCache)
+	require.Equal(t, 2, fetches)
+	require.Equal(t, 1, notModified)
+	require.Equal(t, "Wed, 21 Oct 2015 07:28:00 GMT", gotModifiedSince)
+	require.Equal(t, first.Data.(*Film).ID, second.Data.(*Film).ID)
+	require.Equal(t, "48640", second.Data.(*Film).ID)
+
+	// Without a cache there is nothing to validate against
+	c = New(WithBaseURL(hsrv.URL), WithNoCache())
+	_, resp, err = c.sendRequest(mustNewGetRequest(hsrv.URL+"/film/sweet-sweetbacks-baadasssss-song/"), extractFilmFromFilmPage)
+	require.NoError(t, err)
+	require.False(t, resp.FromCache)
+	require.Empty(t, gotETag)
+}
+
+func TestWithMaxResponseSize(t *testing.T) {
+	hsrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Stream the page out in chunks, well past the limit
+		for i := 0; i < 64; i++ {
+			fmt.Fprint(w, strings.Repeat("<p>filler</p>", 128))
+			w.(http.Flusher).Flush()
+		}
+	}))
+	defer hsrv.Close()
+
+	c := New(WithNoCache(), WithBaseURL(hsrv.URL), WithMaxResponseSize(4096))
+	_, _, err := c.sendRequest(mustNewGetRequest(hsrv.URL+"/film/huge/"), extractFilmFromFilmPage)
+	require.ErrorIs(t, err, ErrResponseTooLarge)
+
+	// A page right at the limit is fine
+	c = New(WithNoCache(), WithBaseURL(hsrv.URL), WithMaxResponseSize(int64(64*128*len("<p>filler</p>"))))
+	_, _, err = c.sendRequest(mustNewGetRequest(hsrv.URL+"/film/huge/"), extractFilmFromFilmPage)
+	require.NoError(t, err)
+
+	// The default is plenty for real pages
+	require.Equal(t, int64(maxResponseSize), New(WithNoCache()).maxResponseSize)
+	_, _, err = sc.sendRequest(mustNewGetRequest(srv.URL+"/film/sweet-sweetbacks-baadasssss-song"), extractFilmFromFilmPage)
+	require.NoError(t, err)
+}
+
+func TestWithStrictExtraction(t *testing.T) {
+	strict := New(WithNoCache(), WithBaseURL(srv.URL), WithStrictExtraction(true))
+	_, _, err := strict.sendRequest(mustNewGetRequest(srv.URL+"/nostats"), ExtractUser)
+	require.ErrorIs(t, err, ErrStrictExtraction)
+	require.EqualError(t, err, "strict extraction failed for /nostats: no film count stat found")
+
+	// A complete page is fine either way
+	_, _, err = strict.sendRequest(mustNewGetRequest(srv.URL+"/someguy"), ExtractUser)
+	require.NoError(t, err)
+
+	// Lenient by default
+	items, _, err := sc.sendRequest(mustNewGetRequest(srv.URL+"/nostats"), ExtractUser)
+	require.NoError(t, err)
+	require.Equal(t, 0, items.Data.(*User).WatchedFilmCount)
+}
+
+func TestWithStrictExtractionDiary(t *testing.T) {
+	entries := DiaryEntries{
+		NewDiaryEntry(selectWithString(`<span class="diary-entry-edit"><a href="#" data-viewing-date="2022-10-02" data-film-poster="/film/cure/image-150/"></a></span>`).Find(".diary-entry-edit")),
+	}
+	require.Empty(t, entries.extractionWarnings())
+
+	entries = append(entries, NewDiaryEntry(selectWithString(`<span class="diary-entry-edit"><a href="#"></a></span>`).Find(".diary-entry-edit")))
+	require.Equal(t, []string{"no viewing date found", "no film slug found"}, entries.extractionWarnings())
+}
+
+func TestWithWarnWriter(t *testing.T) {
+	var warnings bytes.Buffer
+	c := New(WithNoCache(), WithWarnWriter(&warnings))
+	c.warnf("first: %v", 1)
+	c.warnf("second: %v", 2)
+	require.Equal(t, "first: 1\nsecond: 2\n", warnings.String())
+
+	// nil throws warnings away
+	c = New(WithNoCache(), WithWarnWriter(nil))
+	c.warnf("dropped")
+}
+
+func TestWithProxy(t *testing.T) {
+	for _, proxy := range []string{"http://proxy.example.com:3128", "socks5://127.0.0.1:1080"} {
+		c := New(WithNoCache(), WithProxy(proxy))
+		tr, ok := c.client.Transport.(*http.Transport)
+		require.True(t, ok)
+		got, err := tr.Proxy(mustNewGetRequest("https://letterboxd.com/film/cure/"))
+		require.NoError(t, err)
+		require.Equal(t, proxy, got.String())
+	}
+
+	for _, proxy := range []string{"ftp://proxy.example.com", "not a url", "://"} {
+		c := New(WithNoCache(), WithProxy(proxy))
+		_, err := c.Film.Get(context.TODO(), "cure")
+		require.ErrorIs(t, err, ErrInvalidProxy, proxy)
+	}
+
+	// The callers transport is copied, not changed
+	tr := &http.Transport{}
+	hc := &http.Client{Transport: tr}
+	c := New(WithNoCache(), WithHTTPClient(hc), WithProxy("http://proxy.example.com:3128"))
+	require.Nil(t, tr.Proxy)
+	require.Same(t, tr, hc.Transport)
+	require.NotSame(t, tr, c.client.Transport)
+
+	// Transports that can not take a proxy are reported
+	c = New(WithNoCache(), WithHTTPClient(&http.Client{Transport: &countingTransport{}}), WithProxy("http://proxy.example.com:3128"))
+	_, err := c.Film.Get(context.TODO(), "cure")
+	require.ErrorIs(t, err, ErrInvalidProxy)
+}
+
+func TestWithBaseURLTrailingSlash(t *testing.T) {
+	c := New(WithNoCache(), WithBaseURL(srv.URL+"/"))
+	require.Equal(t, srv.URL, c.baseURL)
+
+	tr := &countingTransport{prefix: "//"}
+	c.client.Transport = tr
+	film, err := c.Film.Get(context.TODO(), "sweet-sweetbacks-baadasssss-song")
+	require.NoError(t, err)
+	require.Equal(t, "48640", film.ID)
+	user, _, err := c.User.Profile(context.TODO(), "someguy")
+	require.NoError(t, err)
+	require.Equal(t, "dankmccoy", user.Username)
+	require.Equal(t, 0, tr.count)
+}
+
+func TestWithAssetBaseURL(t *testing.T) {
+	require.Equal(t, "https://a.ltrbxd.com/1.jpg", New(WithNoCache()).assetURL("/1.jpg"))
+	c := New(WithNoCache(), WithAssetBaseURL("https://cdn.example.com"))
+	require.Equal(t, "https://cdn.example.com/1.jpg", c.assetURL("/1.jpg"))
+	require.Equal(t, "https://cdn.example.com/1.jpg", c.assetURL("//cdn.example.com/1.jpg"))
+}
+
+func TestNewWithConfig(t *testing.T) {
+	hc := &http.Client{Timeout: time.Second * 5}
+	cacheTime := time.Hour
+	db, _ := redismock.NewClientMock()
+	c := NewWithConfig(ClientConfig{
+		HTTPClient:         hc,
+		BaseURL:            srv.URL,
+		MaxConcurrentPages: 3,
+		DisableCache:       true,
+		RedisHost:          "127.0.0.1:6380",
+		RedisPassword:      "hunter2",
+		RedisDB:            2,
+		Cache:              cache.New(&cache.Options{Redis: db}),
+		CacheTime:          &cacheTime,
+		RedisClient:        db,
+	})
+	require.Equal(t, hc, c.client)
+	require.Equal(t, srv.URL, c.baseURL)
+	require.Equal(t, 3, c.MaxConcurrentPages)
+	require.Equal(t, time.Hour, c.cacheTTL(time.Minute))
+	// DisableCache wins over the other cache settings
+	require.Nil(t, c.Cache)
+
+	// The client still works
+	film, err := c.Film.Get(context.TODO(), "sweet-sweetbacks-baadasssss-song")
+	require.NoError(t, err)
+	require.Equal(t, "48640", film.ID)
+}
+
+func TestNewWithConfigCache(t *testing.T) {
+	db, _ := redismock.NewClientMock()
+	cc := cache.New(&cache.Options{Redis: db})
+	c := NewWithConfig(ClientConfig{Cache: cc, RedisHost: "127.0.0.1:6380"})
+	require.Equal(t, cc, c.Cache)
+
+	c = NewWithConfig(ClientConfig{RedisClient: db})
+	require.NotNil(t, c.Cache)
+	require.NotEqual(t, cc, c.Cache)
+
+	// Zero values keep the New defaults
+	c = NewWithConfig(ClientConfig{})
+	require.Equal(t, baseURL, c.baseURL)
+	require.Equal(t, maxPages, c.MaxConcurrentPages)
+	require.NotNil(t, c.Cache)
+	require.Equal(t, time.Minute, c.cacheTTL(time.Minute))
+}
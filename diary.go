@@ -1,26 +1,274 @@
 package letterboxd
 
 import (
+	"encoding/csv"
 	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 )
 
+const (
+	// MinRating is the bottom of the 0-10 rating scale. A rating of 0 means
+	// the entry has no rating
+	MinRating = 0
+	// MaxRating is the top of the 0-10 rating scale, which is 5 stars
+	MaxRating = 10
+)
+
 // DiaryEntry is a specific film from a users Diary
 type DiaryEntry struct {
-	Watched       *time.Time
-	Rating        *int
-	Rewatch       bool
-	SpecifiedDate bool
-	Film          *Film
-	Slug          *string
+	ViewingID       string
+	Watched         *time.Time
+	Rating          *int
+	Rewatch         bool
+	SpecifiedDate   bool
+	Liked           bool
+	ReviewLikeCount int // Likes on the review for this entry, not the film
+	WatchNumber     int // Which watch of the film this was, 1 being the first. 0 if unknown
+	Film            *Film
+	Slug            *string
+	Review          *Review // Only fetched with DiaryOpts.IncludeReviews, nil if the entry has no review
+	reviewPath      string
+	warnings        []string
+}
+
+// DiaryOpts are the options for what is fetched along with each diary entry
+type DiaryOpts struct {
+	// IncludeFilms looks up the full film page for every entry. Without it,
+	// Film only has what the diary page shows: ID, Title, Slug and Year
+	IncludeFilms bool
+	// IncludeReviews fetches the review text for entries that have one
+	IncludeReviews bool
+}
+
+// DefaultDiaryOpts returns the options Diary and StreamDiary use
+func DefaultDiaryOpts() DiaryOpts {
+	return DiaryOpts{IncludeFilms: true}
 }
 
 // DiaryEntries is multiple DiaryEntry items
 type DiaryEntries []*DiaryEntry
 
+func (d DiaryEntries) extractionWarnings() []string {
+	var ret []string
+	for _, e := range d {
+		ret = append(ret, e.warnings...)
+	}
+	return ret
+}
+
+// GroupByMonth groups the entries by the month they were watched, keyed like
+// '2006-01'. Entries without a watched date are grouped under an empty key.
+// Ordering within each group is preserved
+func (d DiaryEntries) GroupByMonth() map[string]DiaryEntries {
+	ret := map[string]DiaryEntries{}
+	for _, e := range d {
+		var key string
+		if e.Watched != nil {
+			key = e.Watched.Format("2006-01")
+		}
+		ret[key] = append(ret[key], e)
+	}
+	return ret
+}
+
+// watchDays returns each calendar day with at least one watch, in order.
+// Entries without a watched date are ignored
+func (d DiaryEntries) watchDays() []time.Time {
+	seen := map[time.Time]bool{}
+	var days []time.Time
+	for _, e := range d {
+		if e.Watched == nil {
+			continue
+		}
+		day := time.Date(e.Watched.Year(), e.Watched.Month(), e.Watched.Day(), 0, 0, 0, 0, time.UTC)
+		if !seen[day] {
+			seen[day] = true
+			days = append(days, day)
+		}
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+	return days
+}
+
+// daysBetween returns the number of calendar days from start to end
+func daysBetween(start, end time.Time) int {
+	return int(end.Sub(start).Hours() / 24)
+}
+
+// LongestStreak returns the longest run of consecutive calendar days with at
+// least one watch, and how many days it lasted. The earliest streak wins a tie.
+// Returns zero values when nothing has a watched date
+func (d DiaryEntries) LongestStreak() (start, end time.Time, days int) {
+	watched := d.watchDays()
+	for i := 0; i < len(watched); {
+		j := i
+		for j+1 < len(watched) && daysBetween(watched[j], watched[j+1]) == 1 {
+			j++
+		}
+		if j-i+1 > days {
+			start, end, days = watched[i], watched[j], j-i+1
+		}
+		i = j + 1
+	}
+	return start, end, days
+}
+
+// LongestGap returns the longest stretch between two watch days, as the days
+// on either side of it and the number of days between them, so watches on
+// the 1st and the 5th are a 4 day gap. The earliest gap wins a tie. Returns
+// zero values when there are fewer than two watch days
+func (d DiaryEntries) LongestGap() (start, end time.Time, days int) {
+	watched := d.watchDays()
+	for i := 1; i < len(watched); i++ {
+		if gap := daysBetween(watched[i-1], watched[i]); gap > days {
+			start, end, days = watched[i-1], watched[i], gap
+		}
+	}
+	return start, end, days
+}
+
+// ImportDiaryCSV reads the diary.csv from a Letterboxd data export in to
+// DiaryEntries. Columns are matched by their header, so both the export
+// (Date,Name,Year,Letterboxd URI,Rating,Rewatch,Tags,Watched Date) and
+// similar layouts work. Star ratings are converted to the 0-10 scale. Entries
+// without a watched date use the date they were logged, with SpecifiedDate
+// false. A Review column, when there is one, becomes the entry Review text
+func ImportDiaryCSV(r io.Reader) (DiaryEntries, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("could not read diary csv header: %w", err)
+	}
+	cols := map[string]int{}
+	for i, name := range header {
+		cols[strings.ToLower(strings.ReplaceAll(strings.TrimSpace(name), " ", ""))] = i
+	}
+	if _, ok := cols["name"]; !ok {
+		return nil, errors.New("diary csv has no Name column")
+	}
+
+	entries := DiaryEntries{}
+	for line := 2; ; line++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		field := func(name string) string {
+			if i, ok := cols[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		entry, err := diaryEntryWithCSVFields(field)
+		if err != nil {
+			return nil, fmt.Errorf("diary csv line %d: %w", line, err)
+		}
+		entries = append(entries, entry)
+	}
+}
+
+// diaryEntryWithCSVFields builds a DiaryEntry from a single diary csv row
+func diaryEntryWithCSVFields(field func(string) string) (*DiaryEntry, error) {
+	entry := &DiaryEntry{
+		Film:    &Film{Title: field("name")},
+		Rewatch: strings.EqualFold(field("rewatch"), "yes"),
+	}
+	if year := field("year"); year != "" {
+		y, err := strconv.Atoi(year)
+		if err != nil {
+			return nil, fmt.Errorf("invalid year %q", year)
+		}
+		entry.Film.Year = y
+	}
+	if rating := field("rating"); rating != "" {
+		stars, err := strconv.ParseFloat(rating, 64)
+		halfStars := stars * 2
+		if err != nil || halfStars != math.Trunc(halfStars) || halfStars < MinRating || halfStars > MaxRating {
+			return nil, fmt.Errorf("invalid rating %q", rating)
+		}
+		if halfStars > MinRating {
+			r := int(halfStars)
+			entry.Rating = &r
+		}
+	}
+	date := field("watcheddate")
+	entry.SpecifiedDate = date != ""
+	if date == "" {
+		date = field("date")
+	}
+	if date != "" {
+		watched, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %q", date)
+		}
+		entry.Watched = &watched
+	}
+	if slug, err := SlugFromURL(field("letterboxduri")); err == nil {
+		entry.Slug = &slug
+		entry.Film.Slug = slug
+	}
+	if text := field("review"); text != "" {
+		entry.Review = &Review{Text: text}
+	}
+	return entry, nil
+}
+
+// ExportCSV writes the entries in the diary.csv layout of a Letterboxd data
+// export, so they can be read back with ImportDiaryCSV. The logged date is not
+// kept on a DiaryEntry, so Date is the watched date, and Watched Date is only
+// written for entries with SpecifiedDate. Tags are left empty, and the Review
+// text is added as a last column
+func (d DiaryEntries) ExportCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"Date", "Name", "Year", "Letterboxd URI", "Rating", "Rewatch", "Tags", "Watched Date", "Review"}); err != nil {
+		return err
+	}
+	for _, entry := range d {
+		var name, year, uri, rating, rewatch, date, watchedDate, review string
+		if entry.Film != nil {
+			name = entry.Film.Title
+			if entry.Film.Year != 0 {
+				year = strconv.Itoa(entry.Film.Year)
+			}
+		}
+		if entry.Slug != nil {
+			uri = fmt.Sprintf("https://letterboxd.com/film/%s/", *entry.Slug)
+		}
+		if entry.Rating != nil {
+			rating = strconv.FormatFloat(Rating(*entry.Rating).Stars(), 'f', -1, 64)
+		}
+		if entry.Rewatch {
+			rewatch = "Yes"
+		}
+		if entry.Watched != nil {
+			date = entry.Watched.Format("2006-01-02")
+			if entry.SpecifiedDate {
+				watchedDate = date
+			}
+		}
+		if entry.Review != nil {
+			review = entry.Review.Text
+		}
+		if err := cw.Write([]string{date, name, year, uri, rating, rewatch, "", watchedDate, review}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
 // DiaryFilterOpts provides options for filtering a user diary
 type DiaryFilterOpts struct {
 	Earliest      *time.Time
@@ -29,6 +277,7 @@ type DiaryFilterOpts struct {
 	MaxRating     *int
 	Rewatch       *bool
 	SpecifiedDate *bool
+	Unrated       *bool
 }
 
 type (
@@ -67,9 +316,7 @@ func DiaryFilterMinRating(e DiaryEntry, f DiaryFilterOpts) bool {
 	if f.MinRating == nil {
 		return true
 	}
-	r := e.Rating
-	fr := f.MinRating
-	return *r >= *fr
+	return ratingOrZero(e.Rating) >= *f.MinRating
 }
 
 // DiaryFilterMaxRating filters based on maximum rating
@@ -77,9 +324,23 @@ func DiaryFilterMaxRating(e DiaryEntry, f DiaryFilterOpts) bool {
 	if f.MaxRating == nil {
 		return true
 	}
-	r := e.Rating
-	fr := f.MaxRating
-	return *r <= *fr
+	return ratingOrZero(e.Rating) <= *f.MaxRating
+}
+
+// DiaryFilterUnrated only returns entries that were logged without a rating
+func DiaryFilterUnrated(e DiaryEntry, f DiaryFilterOpts) bool {
+	if f.Unrated == nil {
+		return true
+	}
+	return *f.Unrated == (e.Rating == nil)
+}
+
+// ratingOrZero returns the value of a rating, treating unrated entries as MinRating
+func ratingOrZero(r *int) int {
+	if r == nil {
+		return MinRating
+	}
+	return *r
 }
 
 // DiaryFilterDateSpecified only returns items that actually list the date they were watched
@@ -138,10 +399,11 @@ func BindDiaryFilterWithCobra(cmd *cobra.Command, opts DiaryCobraOpts) {
 	cmd.PersistentFlags().String(prefix+"earliest", "", "Earliest diary entries")
 	cmd.PersistentFlags().String(prefix+"latest", "", "Latest diary entries")
 	cmd.PersistentFlags().String(prefix+"year", "", "Only use entries from the given year")
-	cmd.PersistentFlags().Int(prefix+"min-rating", 0, "Minimum rating for entries")
-	cmd.PersistentFlags().Int(prefix+"max-rating", 10, "Maximum rating for entries")
+	cmd.PersistentFlags().Int(prefix+"min-rating", MinRating, "Minimum rating for entries")
+	cmd.PersistentFlags().Int(prefix+"max-rating", MaxRating, "Maximum rating for entries")
 	cmd.PersistentFlags().Bool(prefix+"rewatched", false, "Only return re-watched entries")
 	cmd.PersistentFlags().Bool(prefix+"date-specified", false, "Only return entries with a date specified")
+	cmd.PersistentFlags().Bool(prefix+"unrated", false, "Only return entries without a rating")
 	cmd.MarkFlagsMutuallyExclusive(prefix+"year", prefix+"earliest")
 	cmd.MarkFlagsMutuallyExclusive(prefix+"year", prefix+"latest")
 }
@@ -205,6 +467,14 @@ func DiaryFilterWithCobra(cmd *cobra.Command, dopts DiaryCobraOpts) (*DiaryFilte
 		}
 		opts.SpecifiedDate = &dateSpecified
 	}
+
+	if cmd.PersistentFlags().Changed(prefix + "unrated") {
+		unrated, err := cmd.Flags().GetBool(prefix + "unrated")
+		if err != nil {
+			return nil, err
+		}
+		opts.Unrated = &unrated
+	}
 	return opts, nil
 }
 
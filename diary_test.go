@@ -2,10 +2,13 @@ package letterboxd
 
 import (
 	"bytes"
+	"fmt"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/PuerkitoBio/goquery"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/require"
 )
@@ -18,11 +21,22 @@ func TestExtractUserDiary(t *testing.T) {
 	items := itemsI.(DiaryEntries)
 	require.NoError(t, err)
 	require.Equal(t, len(items), 50)
+	require.Equal(t, "300839528", items[0].ViewingID)
 	require.Equal(t, 7, *items[0].Rating)
 	require.Equal(t, "cure", *items[0].Slug)
 	require.Equal(t, true, items[0].SpecifiedDate)
 	require.Equal(t, true, items[0].Rewatch)
 
+	require.Equal(t, false, items[0].Liked)
+
+	var liked int
+	for _, item := range items {
+		if item.Liked {
+			liked++
+		}
+	}
+	require.Equal(t, 29, liked)
+
 	require.NotNil(t, items[0].Film)
 	require.Equal(t, "Sweet Sweetback's Baadasssss Song", items[0].Film.Title)
 }
@@ -196,3 +210,264 @@ func TestTimeWithCobraStringErr(t *testing.T) {
 	require.Error(t, err)
 	require.Nil(t, got)
 }
+
+func TestDiaryFilterUnrated(t *testing.T) {
+	r := 6
+	truthy := true
+	falsey := false
+	entries := DiaryEntries{
+		{Rating: &r},
+		{Liked: true},
+		{Rating: &r, Liked: true},
+	}
+	require.Equal(t, 3, len(ApplyDiaryFilters(entries, DiaryFilterOpts{}, DiaryFilterUnrated)))
+
+	got := ApplyDiaryFilters(entries, DiaryFilterOpts{Unrated: &truthy}, DiaryFilterUnrated)
+	require.Equal(t, 1, len(got))
+	require.Nil(t, got[0].Rating)
+	require.True(t, got[0].Liked)
+
+	require.Equal(t, 2, len(ApplyDiaryFilters(entries, DiaryFilterOpts{Unrated: &falsey}, DiaryFilterUnrated)))
+
+	// Unrated entries should not blow up the rating filters
+	mr := 5
+	require.Equal(t, 2, len(ApplyDiaryFilters(entries, DiaryFilterOpts{MinRating: &mr}, DiaryFilterMinRating)))
+	require.Equal(t, 1, len(ApplyDiaryFilters(entries, DiaryFilterOpts{MaxRating: &mr}, DiaryFilterMaxRating)))
+}
+
+func TestNewDiaryEntryUnrated(t *testing.T) {
+	sel := selectWithString(`<table><tr class="diary-entry-row"><td><span class="has-icon icon-16 large-liked icon-liked hide-for-owner"></span></td>
+<td><span class="diary-entry-edit"><a href="#" data-rating="0" data-viewing-date="2022-10-02"></a></span></td></tr></table>`)
+	entry := NewDiaryEntry(sel.Find(".diary-entry-edit"))
+	require.Nil(t, entry.Rating)
+	require.True(t, entry.Liked)
+}
+
+func TestDiaryFilterWithCobraUnrated(t *testing.T) {
+	cmd := &cobra.Command{}
+	BindDiaryFilterWithCobra(cmd, DiaryCobraOpts{})
+	cmd.SetArgs([]string{"--unrated"})
+	cmd.Execute()
+	opts, err := DiaryFilterWithCobra(cmd, DiaryCobraOpts{})
+	require.NoError(t, err)
+	require.NotNil(t, opts.Unrated)
+	require.True(t, *opts.Unrated)
+}
+
+func TestNewDiaryEntryReviewLikes(t *testing.T) {
+	f, err := os.Open("testdata/user/diary-review-likes.html")
+	require.NoError(t, err)
+	defer f.Close()
+	doc := mustNewDocumentFromReader(f)
+	var entries DiaryEntries
+	doc.Find(".diary-entry-edit").Each(func(i int, s *goquery.Selection) {
+		entries = append(entries, NewDiaryEntry(s))
+	})
+	require.Equal(t, 2, len(entries))
+	require.Equal(t, 1024, entries[0].ReviewLikeCount)
+	require.False(t, entries[0].Liked)
+	require.Equal(t, 0, entries[1].ReviewLikeCount)
+}
+
+func TestNewDiaryEntryGlyphRating(t *testing.T) {
+	f, err := os.Open("testdata/user/diary-glyph-rating.html")
+	require.NoError(t, err)
+	defer f.Close()
+	doc := mustNewDocumentFromReader(f)
+	var entries DiaryEntries
+	doc.Find(".diary-entry-edit").Each(func(i int, s *goquery.Selection) {
+		entries = append(entries, NewDiaryEntry(s))
+	})
+	require.Equal(t, 2, len(entries))
+	require.NotNil(t, entries[0].Rating)
+	require.Equal(t, 7, *entries[0].Rating)
+	require.NotNil(t, entries[1].Rating)
+	require.Equal(t, 4, *entries[1].Rating)
+}
+
+func TestNewDiaryEntryWatchNumber(t *testing.T) {
+	f, err := os.Open("testdata/user/diary-watch-number.html")
+	require.NoError(t, err)
+	defer f.Close()
+	doc := mustNewDocumentFromReader(f)
+	var entries DiaryEntries
+	doc.Find(".diary-entry-edit").Each(func(i int, s *goquery.Selection) {
+		entries = append(entries, NewDiaryEntry(s))
+	})
+	require.Equal(t, 2, len(entries))
+	require.True(t, entries[0].Rewatch)
+	require.Equal(t, 3, entries[0].WatchNumber)
+	require.False(t, entries[1].Rewatch)
+	require.Equal(t, 1, entries[1].WatchNumber)
+
+	// A rewatch without the count is unknown
+	sel := selectWithString(`<table><tr class="diary-entry-row"><td class="td-rewatch center"><span class="has-icon icon-rewatch icon-16"></span></td>
+<td><span class="diary-entry-edit"><a href="#" data-rewatch="true" data-viewing-date="2022-10-02"></a></span></td></tr></table>`)
+	require.Equal(t, 0, NewDiaryEntry(sel.Find(".diary-entry-edit")).WatchNumber)
+}
+
+func TestParseRating(t *testing.T) {
+	tests := map[string]struct {
+		val  string
+		want *int
+	}{
+		"numeric":      {val: "7", want: intPtr(7)},
+		"unrated":      {val: "0"},
+		"out-of-range": {val: "11"},
+		"glyphs":       {val: " ★★★½ ", want: intPtr(7)},
+		"half-only":    {val: "½", want: intPtr(1)},
+		"five-stars":   {val: "★★★★★", want: intPtr(10)},
+		"empty":        {val: ""},
+		"garbage":      {val: "great"},
+	}
+	for desc, tt := range tests {
+		require.Equal(t, tt.want, parseRating(tt.val), desc)
+	}
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestRatingConstantDefaults(t *testing.T) {
+	cmd := &cobra.Command{}
+	BindDiaryFilterWithCobra(cmd, DiaryCobraOpts{})
+	require.Equal(t, fmt.Sprint(MinRating), cmd.PersistentFlags().Lookup("min-rating").DefValue)
+	require.Equal(t, fmt.Sprint(MaxRating), cmd.PersistentFlags().Lookup("max-rating").DefValue)
+
+	opts, err := DiaryFilterWithCobra(cmd, DiaryCobraOpts{})
+	require.NoError(t, err)
+	require.Equal(t, MinRating, *opts.MinRating)
+	require.Equal(t, MaxRating, *opts.MaxRating)
+}
+
+func TestDiaryEntriesGroupByMonth(t *testing.T) {
+	jan1, _ := time.Parse("2006-01-02", "2022-01-03")
+	jan2, _ := time.Parse("2006-01-02", "2022-01-28")
+	mar, _ := time.Parse("2006-01-02", "2022-03-15")
+	otherJan, _ := time.Parse("2006-01-02", "2021-01-10")
+	entries := DiaryEntries{
+		{ViewingID: "1", Watched: &jan2},
+		{ViewingID: "2", Watched: &mar},
+		{ViewingID: "3"},
+		{ViewingID: "4", Watched: &jan1},
+		{ViewingID: "5", Watched: &otherJan},
+	}
+	got := entries.GroupByMonth()
+	require.Equal(t, 4, len(got))
+	require.Equal(t, DiaryEntries{entries[0], entries[3]}, got["2022-01"])
+	require.Equal(t, DiaryEntries{entries[1]}, got["2022-03"])
+	require.Equal(t, DiaryEntries{entries[4]}, got["2021-01"])
+	require.Equal(t, DiaryEntries{entries[2]}, got[""])
+
+	require.Empty(t, DiaryEntries{}.GroupByMonth())
+}
+
+func TestDiaryEntriesStreakAndGap(t *testing.T) {
+	day := func(d int, hour int) *time.Time {
+		w := time.Date(2022, time.March, d, hour, 0, 0, 0, time.UTC)
+		return &w
+	}
+	entries := DiaryEntries{
+		{Watched: day(12, 20)},
+		{Watched: day(1, 10)},
+		{Watched: day(2, 22)},
+		{Watched: day(2, 23)},
+		{Watched: nil},
+		{Watched: day(3, 1)},
+		{Watched: day(4, 18)},
+		{Watched: day(13, 9)},
+		{Watched: day(20, 9)},
+	}
+
+	start, end, days := entries.LongestStreak()
+	require.Equal(t, *day(1, 0), start)
+	require.Equal(t, *day(4, 0), end)
+	require.Equal(t, 4, days)
+
+	start, end, days = entries.LongestGap()
+	require.Equal(t, *day(4, 0), start)
+	require.Equal(t, *day(12, 0), end)
+	require.Equal(t, 8, days)
+
+	// A single watch day is a 1 day streak, but has no gaps
+	single := DiaryEntries{{Watched: day(5, 12)}, {Watched: day(5, 13)}}
+	_, _, days = single.LongestStreak()
+	require.Equal(t, 1, days)
+	start, _, days = single.LongestGap()
+	require.True(t, start.IsZero())
+	require.Equal(t, 0, days)
+
+	// Nothing watched
+	start, end, days = DiaryEntries{{}}.LongestStreak()
+	require.True(t, start.IsZero())
+	require.True(t, end.IsZero())
+	require.Equal(t, 0, days)
+}
+
+func TestImportDiaryCSV(t *testing.T) {
+	f, err := os.Open("testdata/user/diary.csv")
+	require.NoError(t, err)
+	defer f.Close()
+	entries, err := ImportDiaryCSV(f)
+	require.NoError(t, err)
+	require.Equal(t, 4, len(entries))
+
+	cure := entries[0]
+	require.Equal(t, "Cure", cure.Film.Title)
+	require.Equal(t, 1997, cure.Film.Year)
+	require.Equal(t, "cure", *cure.Slug)
+	require.Equal(t, 7, *cure.Rating)
+	require.True(t, cure.Rewatch)
+	require.True(t, cure.SpecifiedDate)
+	require.Equal(t, "2022-10-01", cure.Watched.Format("2006-01-02"))
+
+	// Short links don't have a slug
+	require.Nil(t, entries[1].Slug)
+	require.Equal(t, 4, *entries[1].Rating)
+	require.False(t, entries[1].Rewatch)
+
+	// No rating, and no watched date, so the logged date is used
+	require.Nil(t, entries[2].Rating)
+	require.False(t, entries[2].SpecifiedDate)
+	require.Equal(t, "2022-09-28", entries[2].Watched.Format("2006-01-02"))
+
+	require.Equal(t, MaxRating, *entries[3].Rating)
+}
+
+func TestDiaryExportCSVRoundTrip(t *testing.T) {
+	f, err := os.Open("testdata/user/diary.csv")
+	require.NoError(t, err)
+	defer f.Close()
+	entries, err := ImportDiaryCSV(f)
+	require.NoError(t, err)
+	entries[0].Review = &Review{Text: "Creepy, and great.\nWould watch again."}
+
+	var buf bytes.Buffer
+	require.NoError(t, entries.ExportCSV(&buf))
+	require.True(t, strings.HasPrefix(buf.String(), "Date,Name,Year,Letterboxd URI,Rating,Rewatch,Tags,Watched Date,Review\n"))
+	got, err := ImportDiaryCSV(&buf)
+	require.NoError(t, err)
+	require.Equal(t, entries, got)
+}
+
+func TestImportDiaryCSVLayout(t *testing.T) {
+	entries, err := ImportDiaryCSV(strings.NewReader("Date,Name,Year,Rating,Rewatch,Tags,WatchedDate,Review\n2022-10-02,Cure,1997,0.5,No,,2022-10-01,\"Creepy, great\"\n"))
+	require.NoError(t, err)
+	require.Equal(t, 1, len(entries))
+	require.Equal(t, 1, *entries[0].Rating)
+	require.Equal(t, "2022-10-01", entries[0].Watched.Format("2006-01-02"))
+	require.Equal(t, "Creepy, great", entries[0].Review.Text)
+
+	for desc, in := range map[string]string{
+		"no-name":     "Date,Year\n2022-10-02,1997\n",
+		"bad-rating":  "Name,Rating\nCure,3.25\n",
+		"high-rating": "Name,Rating\nCure,6\n",
+		"bad-date":    "Name,Watched Date\nCure,10/01/2022\n",
+		"bad-year":    "Name,Year\nCure,nineteen\n",
+		"empty":       "",
+	} {
+		_, err := ImportDiaryCSV(strings.NewReader(in))
+		require.Error(t, err, desc)
+	}
+}
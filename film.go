@@ -2,11 +2,16 @@ package letterboxd
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
+	"net/url"
 	"os"
+	"path"
+	"regexp"
 	"strconv"
 	"strings"
 	"sync"
@@ -24,17 +29,118 @@ type ExternalFilmIDs struct {
 
 // Film represents a Letterboxd Film
 type Film struct {
-	ID          string           `json:"id"`
-	Title       string           `json:"title"`
-	Slug        string           `json:"slug"`
-	Target      string           `json:"target"`
-	Year        int              `json:"year"`
-	ExternalIDs *ExternalFilmIDs `json:"external_ids,omitempty"`
+	ID                string           `json:"id"`
+	Title             string           `json:"title"`
+	Slug              string           `json:"slug"`
+	Target            string           `json:"target"`
+	Year              int              `json:"year"`
+	ExternalIDs       *ExternalFilmIDs `json:"external_ids,omitempty"`
+	Rank              int              `json:"rank,omitempty"` // Position of the film in a ranked list
+	Note              string           `json:"note,omitempty"` // Notes the list owner left on the film
+	PosterURL         string           `json:"poster_url,omitempty"`
+	PosterURLs        map[int]string   `json:"poster_urls,omitempty"`   // The poster sizes the page linked to, keyed by width in pixels
+	HasPoster         bool             `json:"has_poster,omitempty"`    // False when PosterURL is missing or the placeholder for films without a poster
+	PosterWidth       int              `json:"poster_width,omitempty"`  // Poster dimensions in pixels, 0 when the page doesn't say
+	PosterHeight      int              `json:"poster_height,omitempty"` // Poster dimensions in pixels, 0 when the page doesn't say
+	PosterColor       string           `json:"poster_color,omitempty"`  // Average poster color, like '#2b3a4f'
+	BackdropURL       string           `json:"backdrop_url,omitempty"`
+	AverageRating     float64          `json:"average_rating,omitempty"` // Average member rating, out of 5
+	RatingCount       int              `json:"rating_count,omitempty"`   // Members who rated the film, only known from the film page
+	UserRating        *int             `json:"user_rating,omitempty"`    // 0-10 rating from the poster on a users film grid, nil if unrated
+	TrailerURL        string           `json:"trailer_url,omitempty"`
+	Runtime           int              `json:"runtime,omitempty"`  // In minutes, 0 if unknown
+	IsShort           bool             `json:"is_short,omitempty"` // Runtime of shortFilmMaxMinutes or less
+	Releases          []Release        `json:"releases,omitempty"`
+	Themes            []string         `json:"themes,omitempty"` // Themes and nanogenres, when the page shows them
+	Genres            []string         `json:"genres,omitempty"`
+	Countries         []string         `json:"countries,omitempty"`
+	Languages         []string         `json:"languages,omitempty"`          // Primary and spoken languages, without duplicates
+	AlternativeTitles []string         `json:"alternative_titles,omitempty"` // Other names the film is known by, like translated titles. Titles with a ", " in them come back split
+	PopularityRank    int              `json:"popularity_rank,omitempty"`    // Position across all pages of List, 0 when shuffled
+	WatchlistPosition int              `json:"watchlist_position,omitempty"` // Position in the watchlist order from WatchListBy
+	YearUnknown       bool             `json:"year_unknown,omitempty"`       // The film page has no year, so there is no point looking again
+	// FriendsWatchedCount and FriendsLikedCount come from the friends
+	// activity on a film page, which is only shown when signed in using a
+	// session cookie in WithDefaultHeaders. Always 0 otherwise
+	FriendsWatchedCount int `json:"friends_watched_count,omitempty"`
+	FriendsLikedCount   int `json:"friends_liked_count,omitempty"`
+	// Credits are the cast and crew from the film page
+	Credits *Credits `json:"credits,omitempty"`
 }
 
+// Credits are the cast and crew of a film
+type Credits struct {
+	Cast []CastMember `json:"cast,omitempty"`
+	Crew []CrewMember `json:"crew,omitempty"`
+}
+
+// CastMember is an actor credited on a film
+type CastMember struct {
+	Name      string `json:"name"`
+	Slug      string `json:"slug"`                // Person slug, for PersonService.Get
+	Character string `json:"character,omitempty"` // Empty when the film page doesn't name the role
+}
+
+// CrewMember is someone credited on a film for anything other than acting
+type CrewMember struct {
+	Name string `json:"name"`
+	Slug string `json:"slug"` // Person slug, for PersonService.Get
+	Job  string `json:"job"`  // Example: 'director', 'writer' or 'composer'
+}
+
+// CrewWithJob returns the crew credited with job, like 'director'
+func (c *Credits) CrewWithJob(job string) []CrewMember {
+	var crew []CrewMember
+	for _, member := range c.Crew {
+		if member.Job == job {
+			crew = append(crew, member)
+		}
+	}
+	return crew
+}
+
+// Release is a dated release of a film in a single country
+type Release struct {
+	Type    string    `json:"type"` // Example: 'Theatrical', 'Physical' or 'Premiere'
+	Country string    `json:"country"`
+	Date    time.Time `json:"date"`
+}
+
+// ErrNoFilmography is returned when a person exists, but has no films under
+// the requested profession
+var ErrNoFilmography = errors.New("no films found for that person and profession")
+
+// shortFilmMaxMinutes is the longest runtime that is still considered a short
+// film, matching the Academy definition
+const shortFilmMaxMinutes = 40
+
+// posterSizeRegex matches the size of a resized poster, like
+// '48640-sweet-sweetback-s-baadasssss-song-0-230-0-345-crop.jpg'
+var posterSizeRegex = regexp.MustCompile(`-0-(\d+)-0-\d+-crop\.`)
+
+var runtimeRegex = regexp.MustCompile(`([0-9,]+)[\s\x{00a0}]*mins?\b`)
+
+// ErrAgeGated is returned when a film page is behind the adult content age
+// gate. Pass the session cookie of an account that has confirmed its age with
+// WithDefaultHeaders to get past it
+var ErrAgeGated = errors.New("film page is behind an age gate")
+
 // Professions is a string array of all the professions this module cares about
 var Professions = []string{"actor", "director", "producer", "writer"}
 
+// PosterSizes are the poster grid sizes a filmography can be fetched with
+var PosterSizes = []string{"small", "large"}
+
+// SimilarKinds are the kinds of similar films that can be fetched for a film
+var SimilarKinds = []string{"similar", "genre", "theme"}
+
+// similarPaths are the film sub-pages for each of the SimilarKinds
+var similarPaths = map[string]string{
+	"similar": "similar/",
+	"genre":   "similar/genre/",
+	"theme":   "similar/theme/",
+}
+
 // FilmService defines a service to handle methods against Letterboxd films
 type FilmService interface {
 	EnhanceFilm(context.Context, *Film) error
@@ -43,9 +149,18 @@ type FilmService interface {
 	Get(context.Context, string) (*Film, error)
 	GetWatchedIMDBIDs(context.Context, string) ([]string, error)
 	ExtractFilmsWithPath(context.Context, string) (FilmSet, *Pagination, error)
+	Page(context.Context, string) (FilmSet, *Pagination, error)
 	ExtractEnhancedFilmsWithPath(context.Context, string) (FilmSet, *Pagination, error)
 	StreamBatch(context.Context, *FilmBatchOpts, chan *Film, chan error)
 	List(context.Context, *FilmListOpts) (FilmSet, error)
+	Reviewers(context.Context, string, int) ([]string, error)
+	ListedBy(context.Context, string, int) ([]*List, error)
+	Similar(context.Context, string, string) (FilmSet, error)
+	Ratings(context.Context, string) (*RatingsBreakdown, error)
+	Statistics(context.Context, string) (*FilmStatistics, error)
+	Releases(context.Context, string) ([]Release, error)
+	Random(context.Context, *FilmListOpts) (*Film, error)
+	WatchedBy(context.Context, string, float64, int) ([]string, error)
 }
 
 // FilmListOpts options for listing films
@@ -64,6 +179,10 @@ type FilmServiceOp struct {
 type FilmographyOpt struct {
 	Person     string // Person whos filmography is to be fetched
 	Profession string // Profession of the person (actor, writer, director)
+	// IncludeEnhanced looks up every film page for the full details. Defaults
+	// to true when nil; set it to false when only slugs and titles are needed
+	IncludeEnhanced *bool
+	PosterSize      string // Poster grid size, one of PosterSizes. Uses the Letterboxd default when empty
 }
 
 // List lists out all films using the given options
@@ -87,9 +206,46 @@ func (f *FilmServiceOp) List(ctx context.Context, opts *FilmListOpts) (FilmSet,
 			allFilms = append(allFilms, films...)
 		}
 	}
+	// Shuffled pages are out of order, so the position means nothing
+	if !opts.ShufflePages {
+		for i, film := range allFilms {
+			film.PopularityRank = i + 1
+		}
+	}
 	return allFilms, nil
 }
 
+// Random returns a single random, enhanced film from the films list. Any page,
+// including the first, may be picked, so opts.ShufflePages and opts.PageCount
+// are ignored
+func (f *FilmServiceOp) Random(ctx context.Context, opts *FilmListOpts) (*Film, error) {
+	if opts == nil {
+		opts = &FilmListOpts{}
+	}
+	sortBy := stringOr(opts.SortBy, "popular")
+	films, pagination, err := f.ExtractFilmsWithPath(ctx, fmt.Sprintf("/films/ajax/%v/size/small/page/1", sortBy))
+	if err != nil {
+		return nil, err
+	}
+	// The first page is already here if it gets picked
+	if pagination != nil && pagination.TotalPages > 1 {
+		if page := randomPage(pagination.TotalPages); page != 1 {
+			films, _, err = f.ExtractFilmsWithPath(ctx, fmt.Sprintf("/films/ajax/%v/size/small/page/%v", sortBy, page))
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(films) == 0 {
+		return nil, errors.New("no films found to pick from")
+	}
+	film := films[randIntn(len(films))]
+	if err := f.EnhanceFilm(ctx, film); err != nil {
+		return nil, err
+	}
+	return film, nil
+}
+
 // Validate ensures that filmography options contains the appropriate fields
 func (f *FilmographyOpt) Validate() error {
 	switch {
@@ -99,6 +255,8 @@ func (f *FilmographyOpt) Validate() error {
 		return fmt.Errorf("profession is required")
 	case !stringInSlice(f.Profession, Professions):
 		return fmt.Errorf("profession must be one of %v", Professions)
+	case f.PosterSize != "" && !stringInSlice(f.PosterSize, PosterSizes):
+		return fmt.Errorf("poster size must be one of %v", PosterSizes)
 	default:
 		return nil
 	}
@@ -109,71 +267,146 @@ type FilmBatchOpts struct {
 	Watched   []string  `json:"watched"`
 	List      []*ListID `json:"list"`
 	WatchList []string  `json:"watchlist"`
+	// Limit stops the batch after this many films in total, across all
+	// sources. 0 means no limit
+	Limit int `json:"limit,omitempty"`
 }
 
-func loopFilmC(filmsC, userFilmC chan *Film, done, userDone chan error) {
-	for loop := true; loop; {
+// loopFilmC hands films from a single source to send until the source is done,
+// returning the error the source finished with
+func loopFilmC(send func(*Film), sourceFilmC chan *Film, sourceDone chan error) error {
+	for {
 		select {
-		case film := <-userFilmC:
-			filmsC <- film
-		case err := <-userDone:
-			if err != nil {
-				done <- err
-			}
-			loop = false
+		case film := <-sourceFilmC:
+			send(film)
+		case err := <-sourceDone:
+			return err
 		}
 	}
 }
 
-// StreamBatch Get a bunch of different films at once and stream them back to the user
-func (f *FilmServiceOp) StreamBatch(ctx context.Context, batchOpts *FilmBatchOpts, filmsC chan *Film, done chan error) {
-	defer func() {
-		done <- nil
-	}()
+// batchSource is a single stream of films that makes up part of a batch. name
+// says which source it is in errors, like 'watched "dave"'
+type batchSource struct {
+	name   string
+	stream func(chan *Film, chan error)
+}
+
+// batchSources returns all the sources that make up a FilmBatchOpts
+func (f *FilmServiceOp) batchSources(ctx context.Context, batchOpts *FilmBatchOpts) []batchSource {
+	var sources []batchSource
 	for _, username := range batchOpts.Watched {
-		userFilmC := make(chan *Film)
-		userDone := make(chan error)
-		go f.client.User.StreamWatched(ctx, username, userFilmC, userDone)
-		loopFilmC(filmsC, userFilmC, done, userDone)
+		username := username
+		sources = append(sources, batchSource{
+			name: fmt.Sprintf("watched %q", username),
+			stream: func(c chan *Film, d chan error) {
+				f.client.User.StreamWatched(ctx, username, c, d)
+			},
+		})
 	}
 	for _, listID := range batchOpts.List {
-		listFilmC := make(chan *Film)
-		listDone := make(chan error)
-		go f.client.User.StreamList(ctx, listID.User, listID.Slug, listFilmC, listDone)
-		loopFilmC(filmsC, listFilmC, done, listDone)
+		listID := listID
+		sources = append(sources, batchSource{
+			name: fmt.Sprintf("list %q", listID.User+"/"+listID.Slug),
+			stream: func(c chan *Film, d chan error) {
+				f.client.User.StreamList(ctx, listID.User, listID.Slug, c, d)
+			},
+		})
 	}
+	for _, username := range batchOpts.WatchList {
+		username := username
+		sources = append(sources, batchSource{
+			name: fmt.Sprintf("watchlist %q", username),
+			stream: func(c chan *Film, d chan error) {
+				f.client.User.StreamWatchList(ctx, username, c, d)
+			},
+		})
+	}
+	return sources
+}
 
-	for _, user := range batchOpts.WatchList {
-		listFilmC := make(chan *Film)
-		listDone := make(chan error)
-		go f.client.User.StreamWatchList(ctx, user, listFilmC, listDone)
-		loopFilmC(filmsC, listFilmC, done, listDone)
+// StreamBatch Get a bunch of different films at once and stream them back to
+// the user. Each source is streamed concurrently, bounded by the clients
+// MaxConcurrentPages. The first error from any source is sent to done once
+// all sources have finished, prefixed with the source it came from. Once
+// batchOpts.Limit films have been sent, the remaining sources are cancelled
+func (f *FilmServiceOp) StreamBatch(ctx context.Context, batchOpts *FilmBatchOpts, filmsC chan *Film, done chan error) {
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var sendMu sync.Mutex
+	var sent int
+	send := func(film *Film) {
+		sendMu.Lock()
+		defer sendMu.Unlock()
+		if batchOpts.Limit > 0 && sent >= batchOpts.Limit {
+			return
+		}
+		filmsC <- film
+		sent++
+		if batchOpts.Limit > 0 && sent >= batchOpts.Limit {
+			cancel()
+		}
 	}
+
+	sources := f.batchSources(ctx, batchOpts)
+	guard := make(chan struct{}, max(f.client.MaxConcurrentPages, 1))
+	errs := make(chan error, len(sources))
+	var wg sync.WaitGroup
+	wg.Add(len(sources))
+	for _, source := range sources {
+		go func(source batchSource) {
+			defer wg.Done()
+			guard <- struct{}{}
+			defer func() { <-guard }()
+			sourceFilmC := make(chan *Film)
+			sourceDone := make(chan error)
+			go source.stream(sourceFilmC, sourceDone)
+			err := loopFilmC(send, sourceFilmC, sourceDone)
+			// Sources cut short by hitting the limit are not an error
+			if err != nil && !(errors.Is(err, context.Canceled) && parent.Err() == nil) {
+				errs <- fmt.Errorf("%v: %w", source.name, err)
+			}
+		}(source)
+	}
+	wg.Wait()
+	close(errs)
+	// The closed channel gives us the first error, or nil if there were none
+	done <- <-errs
 }
 
-// ExtractFilmsWithPath Given a url path, return a list of films it contains
+// ExtractFilmsWithPath Given a url path, return a list of films it contains.
+// This is the same as Page
 func (f *FilmServiceOp) ExtractFilmsWithPath(ctx context.Context, path string) (FilmSet, *Pagination, error) {
-	// u := mustParseURL(path)
-	// key := fmt.Sprintf("/letterboxd/fullpage%s", u.Path)
-	// var inCache bool
-	var pData *PageData
-	var resp *Response
-	var films FilmSet
-	var url string
-	if strings.HasPrefix(path, "http") {
-		url = path
-	} else {
-		url = fmt.Sprintf("%v%v", f.client.baseURL, path)
-	}
-	req := mustNewGetRequest(url)
+	return f.Page(ctx, path)
+}
 
-	var err error
-	pData, resp, err = f.client.sendRequest(req, ExtractUserFilms)
+// Page returns the films from a single page of any film grid, like a watchlist,
+// a list or the films browser, along with its pagination so the rest can be
+// paged through manually. path may be an absolute URL, or a path relative to
+// the base URL (Example: /dave/list/official-top-250-narrative-feature-films/page/2/).
+// Films are not enhanced, use EnhanceFilmList for that
+func (f *FilmServiceOp) Page(ctx context.Context, path string) (FilmSet, *Pagination, error) {
+	url := path
+	if !strings.HasPrefix(path, "http://") && !strings.HasPrefix(path, "https://") {
+		url = fmt.Sprintf("%v/%v", f.client.baseURL, strings.TrimPrefix(path, "/"))
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, nil, err
 	}
-	defer dclose(resp.Body) // nolint:golint,bodyclose
-	films = pData.Data.(FilmSet)
+	pData, resp, err := f.client.sendRequest(req, ExtractUserFilms)
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.Response != nil {
+		dclose(resp.Body)
+	}
+	films, ok := pData.Data.(FilmSet)
+	if !ok {
+		return nil, nil, errors.New("unexpected data type for films page")
+	}
 	return films, &pData.Pagination, nil
 }
 
@@ -184,8 +417,12 @@ func (f *FilmServiceOp) ExtractEnhancedFilmsWithPath(ctx context.Context, path s
 		return nil, pagination, err
 	}
 
+	// On cancellation, hand back what was enhanced so far along with the error
 	err = f.client.Film.EnhanceFilmList(ctx, &films)
 	if err != nil {
+		if ctx.Err() != nil {
+			return films, pagination, err
+		}
 		return nil, pagination, err
 	}
 
@@ -203,18 +440,38 @@ func filmWithCache(c *cache.Cache, key string) *Film {
 	return nil
 }
 
-// Get returns a single film from the slug
+// Get returns a single film from the slug. A film URL or path may also be used
 func (f *FilmServiceOp) Get(ctx context.Context, slug string) (*Film, error) {
+	slug, err := SlugFromURL(slug)
+	if err != nil {
+		return nil, err
+	}
 	// Determine if we need to get the cached version or not
 	key := fmt.Sprintf("/letterboxd/film/%s", slug)
+	if f.client.filmJSON {
+		// JSON films have fewer fields, so keep them apart from the full ones
+		key = fmt.Sprintf("/letterboxd/film-json/%s", slug)
+	}
 	// var inCache bool
 	if ctx == nil {
 		ctx = context.Background()
 	}
 	retFilm := filmWithCache(f.client.Cache, key)
+	if retFilm != nil {
+		return retFilm, nil
+	}
+
+	if f.client.filmJSON {
+		if retFilm, err = f.filmWithJSON(ctx, slug); err != nil {
+			// Fall back to scraping the film page
+			retFilm = nil
+		} else {
+			f.client.filmAssetURLs(retFilm)
+		}
+	}
 
 	if retFilm == nil {
-		req, err := http.NewRequest("GET", fmt.Sprintf("%s/film/%s", f.client.baseURL, slug), nil)
+		req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/film/%s", f.client.baseURL, slug), nil)
 		if err != nil {
 			return nil, err
 		}
@@ -222,25 +479,34 @@ func (f *FilmServiceOp) Get(ctx context.Context, slug string) (*Film, error) {
 		if err != nil {
 			return nil, err
 		}
-		defer dclose(resp.Body)
+		if resp.Response != nil {
+			defer dclose(resp.Body)
+		}
 		retFilmP := *item.Data.(*Film)
 		retFilm = &retFilmP
+		f.client.filmAssetURLs(retFilm)
+		// Only warned about here, cached films remember the year is unknown
+		if retFilm.YearUnknown {
+			f.client.warnf("no year found for film: %v", slug)
+		}
+	}
 
-		if f.client.Cache != nil {
-			if err := f.client.Cache.Set(&cache.Item{
-				Ctx:   ctx,
-				Key:   key,
-				Value: retFilm,
-				TTL:   time.Hour * 24 * 7,
-			}); err != nil {
-				fmt.Fprintf(os.Stderr, "Error writing cache: %v", err)
-			}
+	if f.client.Cache != nil {
+		if err := f.client.Cache.Set(&cache.Item{
+			Ctx:   ctx,
+			Key:   key,
+			Value: retFilm,
+			TTL:   f.client.cacheTTL(time.Hour * 24 * 7),
+		}); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing cache: %v", err)
 		}
 	}
 	return retFilm, nil
 }
 
-// Filmography returns the Filmography based on certain options
+// Filmography returns the Filmography based on certain options. If the person
+// exists but has no credits for the profession, ErrNoFilmography is returned.
+// If the person does not exist, ErrNotFound is returned
 func (f *FilmServiceOp) Filmography(ctx context.Context, opt *FilmographyOpt) (FilmSet, error) {
 	var films FilmSet
 	err := opt.Validate()
@@ -248,7 +514,11 @@ func (f *FilmServiceOp) Filmography(ctx context.Context, opt *FilmographyOpt) (F
 		return nil, err
 	}
 
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/%s/%s", f.client.baseURL, opt.Profession, opt.Person), nil)
+	path := fmt.Sprintf("%s/%s/%s", f.client.baseURL, opt.Profession, opt.Person)
+	if opt.PosterSize != "" {
+		path = fmt.Sprintf("%s/size/%s/", path, opt.PosterSize)
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", path, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -259,17 +529,15 @@ func (f *FilmServiceOp) Filmography(ctx context.Context, opt *FilmographyOpt) (F
 	defer dclose(resp.Body)
 
 	partialFilms := items.Data.(FilmSet)
-
-	// This is a bit costly, parallel time?
-	err = f.client.Film.EnhanceFilmList(ctx, &partialFilms)
-	if err != nil {
-		return nil, err
+	if len(partialFilms) == 0 {
+		return FilmSet{}, ErrNoFilmography
 	}
 
 	films = append(films, partialFilms...)
-	err = f.client.Film.EnhanceFilmList(ctx, &films)
-	if err != nil {
-		return nil, err
+	if opt.IncludeEnhanced == nil || *opt.IncludeEnhanced {
+		if err := f.client.Film.EnhanceFilmList(ctx, &films); err != nil {
+			return nil, err
+		}
 	}
 
 	return films, nil
@@ -281,9 +549,57 @@ func (f *FilmServiceOp) EnhanceFilm(ctx context.Context, film *Film) error {
 		return errors.New("Film has no slug. Needs that to enhance")
 	}
 	fullFilm, err := f.Get(ctx, film.Slug)
+	// Renamed films can keep a stale slug on their posters, but the target
+	// link still gets to the film
+	if errors.Is(err, ErrNotFound) && film.Target != "" {
+		fullFilm, err = f.filmWithTarget(ctx, film.Target)
+		if err == nil && fullFilm.Slug != "" {
+			film.Slug = fullFilm.Slug
+		}
+	}
 	if err != nil {
 		return errors.New("failed to get film for enhancement")
 	}
+	enhanceWithFilm(film, fullFilm)
+	return nil
+}
+
+// filmWithTarget gets a film from its target link, using the slug from where
+// the link ends up after any redirects
+func (f *FilmServiceOp) filmWithTarget(ctx context.Context, target string) (*Film, error) {
+	u := target
+	if !strings.HasPrefix(target, "http://") && !strings.HasPrefix(target, "https://") {
+		u = fmt.Sprintf("%v/%v", f.client.baseURL, strings.TrimPrefix(target, "/"))
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	item, resp, err := f.client.sendRequest(req, extractFilmFromFilmPage)
+	if err != nil {
+		return nil, err
+	}
+	film := *item.Data.(*Film)
+	if resp.Response != nil {
+		dclose(resp.Body)
+		if slug, err := SlugFromURL(resp.Request.URL.Path); err == nil {
+			film.Slug = slug
+		}
+	}
+	return &film, nil
+}
+
+// filmAssetURLs makes the images of a film absolute against the asset base URL
+func (c *Client) filmAssetURLs(film *Film) {
+	film.PosterURL = c.assetURL(film.PosterURL)
+	film.BackdropURL = c.assetURL(film.BackdropURL)
+	for width, posterURL := range film.PosterURLs {
+		film.PosterURLs[width] = c.assetURL(posterURL)
+	}
+}
+
+// enhanceWithFilm fills in any missing fields on film using fullFilm
+func enhanceWithFilm(film, fullFilm *Film) {
 	if film.Year == 0 {
 		film.Year = fullFilm.Year
 	}
@@ -296,26 +612,461 @@ func (f *FilmServiceOp) EnhanceFilm(ctx context.Context, film *Film) error {
 	if film.ID == "" {
 		film.ID = fullFilm.ID
 	}
+	if film.PosterURL == "" {
+		film.PosterURL = fullFilm.PosterURL
+	}
+	if film.PosterURLs == nil {
+		film.PosterURLs = fullFilm.PosterURLs
+	}
+	if !film.HasPoster {
+		film.HasPoster = fullFilm.HasPoster
+	}
+	if film.PosterWidth == 0 && film.PosterHeight == 0 {
+		film.PosterWidth, film.PosterHeight = fullFilm.PosterWidth, fullFilm.PosterHeight
+	}
+	if film.PosterColor == "" {
+		film.PosterColor = fullFilm.PosterColor
+	}
+	if film.BackdropURL == "" {
+		film.BackdropURL = fullFilm.BackdropURL
+	}
+	if film.TrailerURL == "" {
+		film.TrailerURL = fullFilm.TrailerURL
+	}
+	if film.Runtime == 0 {
+		film.Runtime = fullFilm.Runtime
+	}
+	if film.AverageRating == 0 {
+		film.AverageRating = fullFilm.AverageRating
+	}
+	if film.RatingCount == 0 {
+		film.RatingCount = fullFilm.RatingCount
+	}
+	if !film.IsShort {
+		film.IsShort = fullFilm.IsShort
+	}
+	if film.FriendsWatchedCount == 0 {
+		film.FriendsWatchedCount = fullFilm.FriendsWatchedCount
+	}
+	if film.FriendsLikedCount == 0 {
+		film.FriendsLikedCount = fullFilm.FriendsLikedCount
+	}
+	if film.Releases == nil {
+		film.Releases = fullFilm.Releases
+	}
+	if film.Themes == nil {
+		film.Themes = fullFilm.Themes
+	}
+	if film.Credits == nil {
+		film.Credits = fullFilm.Credits
+	}
+	if film.Genres == nil {
+		film.Genres = fullFilm.Genres
+	}
+	if film.Countries == nil {
+		film.Countries = fullFilm.Countries
+	}
+	if film.Languages == nil {
+		film.Languages = fullFilm.Languages
+	}
+	if film.AlternativeTitles == nil {
+		film.AlternativeTitles = fullFilm.AlternativeTitles
+	}
+	if film.Year == 0 && !film.YearUnknown {
+		film.YearUnknown = fullFilm.YearUnknown
+	}
+}
+
+// enhanceFilmGroup enhances a group of films that all share the same slug,
+// using a single lookup
+func (f *FilmServiceOp) enhanceFilmGroup(ctx context.Context, group []*Film) error {
+	if err := f.EnhanceFilm(ctx, group[0]); err != nil {
+		return err
+	}
+	for _, film := range group[1:] {
+		enhanceWithFilm(film, group[0])
+	}
 	return nil
 }
 
-// EnhanceFilmList takes a list of films, and returns the enhanced version
+// EnhanceFilmList takes a list of films, and returns the enhanced version.
+// Films sharing a slug are only looked up once. If ctx is cancelled, any films
+// not yet looked up are left as they are and ctx.Err() is returned
 func (f *FilmServiceOp) EnhanceFilmList(ctx context.Context, films *FilmSet) error {
+	var groups [][]*Film
+	bySlug := map[string]int{}
+	for _, film := range *films {
+		idx, ok := bySlug[film.Slug]
+		if !ok || film.Slug == "" {
+			bySlug[film.Slug] = len(groups)
+			groups = append(groups, []*Film{film})
+			continue
+		}
+		groups[idx] = append(groups[idx], film)
+	}
+
 	var wg sync.WaitGroup
-	wg.Add(len(*films))
+	wg.Add(len(groups))
 	guard := make(chan struct{}, 5)
-	for _, film := range *films {
-		go func(film *Film) {
+	for _, group := range groups {
+		go func(group []*Film) {
 			defer wg.Done()
-			guard <- struct{}{}
-			if err := f.EnhanceFilm(ctx, film); err != nil {
+			select {
+			case guard <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-guard }()
+			if ctx.Err() != nil {
+				return
+			}
+			if err := f.enhanceFilmGroup(ctx, group); err != nil && ctx.Err() == nil {
 				fmt.Fprintf(os.Stderr, "Failed to get external IDs: %v", err)
 			}
-			<-guard
-		}(film)
+		}(group)
 	}
 	wg.Wait()
-	return nil
+	return ctx.Err()
+}
+
+// Reviewers returns the usernames of people who have recently reviewed a film,
+// most popular first. Use a limit of 0 or less to get as many as possible
+func (f *FilmServiceOp) Reviewers(ctx context.Context, slug string, limit int) ([]string, error) {
+	slug, err := SlugFromURL(slug)
+	if err != nil {
+		return nil, err
+	}
+	reviewers := []string{}
+	for page := 1; page <= maxPages; page++ {
+		req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/film/%s/reviews/by/activity/page/%d/", f.client.baseURL, slug, page), nil)
+		if err != nil {
+			return nil, err
+		}
+		items, resp, err := f.client.sendRequest(req, ExtractReviewers)
+		if err != nil {
+			return nil, err
+		}
+		if resp.Response != nil {
+			dclose(resp.Body)
+		}
+		reviewers = append(reviewers, items.Data.([]string)...)
+		if limit > 0 && len(reviewers) >= limit {
+			return reviewers[:limit], nil
+		}
+		if items.Pagination.IsLast {
+			break
+		}
+	}
+	return reviewers, nil
+}
+
+// ExtractReviewers returns the usernames of reviewers from an io.Reader
+func ExtractReviewers(r io.Reader) (interface{}, *Pagination, error) {
+	doc, err := newDocumentFromReader(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	reviewers := []string{}
+	doc.Find("li.film-detail").Each(func(i int, s *goquery.Selection) {
+		name := strings.Trim(s.Find("a.avatar").AttrOr("href", ""), "/")
+		if name != "" {
+			reviewers = append(reviewers, name)
+		}
+	})
+	pagination := paginationOrSinglePage(doc)
+	return reviewers, pagination, nil
+}
+
+// ListedBy returns the lists a film appears in, along with their owners, most
+// popular first. Use a limit of 0 or less to get as many as possible, up to
+// maxPages of lists
+func (f *FilmServiceOp) ListedBy(ctx context.Context, slug string, limit int) ([]*List, error) {
+	slug, err := SlugFromURL(slug)
+	if err != nil {
+		return nil, err
+	}
+	lists := []*List{}
+	for page := 1; page <= maxPages; page++ {
+		req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/film/%s/lists/by/popular/page/%d/", f.client.baseURL, slug, page), nil)
+		if err != nil {
+			return nil, err
+		}
+		items, resp, err := f.client.sendRequest(req, ExtractFilmLists)
+		if err != nil {
+			return nil, err
+		}
+		if resp.Response != nil {
+			dclose(resp.Body)
+		}
+		lists = append(lists, items.Data.([]*List)...)
+		if limit > 0 && len(lists) >= limit {
+			return lists[:limit], nil
+		}
+		if items.Pagination.IsLast {
+			break
+		}
+	}
+	return lists, nil
+}
+
+// Similar returns previews of the films Letterboxd suggests alongside a film.
+// kind is one of SimilarKinds: 'similar' for the overall suggestions, or
+// 'genre' and 'theme' for the films that share its genres or themes. Films are
+// not enhanced, use EnhanceFilmList for that. An empty kind is 'similar'
+func (f *FilmServiceOp) Similar(ctx context.Context, slug, kind string) (FilmSet, error) {
+	slug, err := SlugFromURL(slug)
+	if err != nil {
+		return nil, err
+	}
+	if kind == "" {
+		kind = "similar"
+	}
+	subPath, ok := similarPaths[kind]
+	if !ok {
+		return nil, fmt.Errorf("kind must be one of %v", SimilarKinds)
+	}
+	films, _, err := f.Page(ctx, fmt.Sprintf("/film/%s/%s", slug, subPath))
+	if err != nil {
+		return nil, err
+	}
+	return films, nil
+}
+
+// RatingsBreakdown is how members have rated a film
+type RatingsBreakdown struct {
+	Average      float64        `json:"average"`      // Weighted average out of 5, 0 until the film has enough ratings
+	Count        int            `json:"count"`        // Total ratings
+	Distribution map[Rating]int `json:"distribution"` // Number of ratings at each half-star
+}
+
+// weightedAverageRegex matches the tooltip on a film histograms average, like
+// 'Weighted average of 4.07 based on 98,512 ratings'
+var weightedAverageRegex = regexp.MustCompile(`Weighted average of ([0-9.]+) based on ([0-9,]+)`)
+
+// ExtractRatingsBreakdown returns the RatingsBreakdown from the ratings
+// histogram of a film
+func ExtractRatingsBreakdown(r io.Reader) (interface{}, *Pagination, error) {
+	doc, err := newDocumentFromReader(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	dist, err := ratingDistributionWithDoc(doc)
+	if err != nil {
+		return nil, nil, err
+	}
+	breakdown := &RatingsBreakdown{Distribution: map[Rating]int{}}
+	for stars, count := range dist {
+		breakdown.Distribution[Rating(stars*2)] = count
+		breakdown.Count += count
+	}
+	if m := weightedAverageRegex.FindStringSubmatch(doc.Find("span.average-rating a").First().AttrOr("title", "")); m != nil {
+		breakdown.Average, _ = strconv.ParseFloat(m[1], 64)
+		if count, err := strconv.Atoi(strings.ReplaceAll(m[2], ",", "")); err == nil {
+			breakdown.Count = count
+		}
+	}
+	return breakdown, nil, nil
+}
+
+// Ratings returns the breakdown of member ratings for a film, from the ratings
+// histogram that the film page loads separately
+func (f *FilmServiceOp) Ratings(ctx context.Context, slug string) (*RatingsBreakdown, error) {
+	slug, err := SlugFromURL(slug)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/csi/film/%s/rating-histogram/", f.client.baseURL, slug), nil)
+	if err != nil {
+		return nil, err
+	}
+	item, resp, err := f.client.sendRequest(req, ExtractRatingsBreakdown)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Response != nil {
+		dclose(resp.Body)
+	}
+	breakdown, ok := item.Data.(*RatingsBreakdown)
+	if !ok {
+		return nil, errors.New("unexpected data type for ratings breakdown")
+	}
+	return breakdown, nil
+}
+
+// FilmStatistics are how many members have engaged with a film
+type FilmStatistics struct {
+	Watches int `json:"watches"` // Members who watched the film
+	Likes   int `json:"likes"`   // Members who liked the film
+	Lists   int `json:"lists"`   // Lists the film appears in
+}
+
+// statCountRegex matches the full count in a film stat tooltip, like
+// 'Watched by 312,456 members'
+var statCountRegex = regexp.MustCompile(`[0-9][0-9,]*`)
+
+// ExtractFilmStatistics returns the FilmStatistics from the stats of a film
+func ExtractFilmStatistics(r io.Reader) (interface{}, *Pagination, error) {
+	doc, err := newDocumentFromReader(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	stats := doc.Find("ul.film-stats")
+	if stats.Length() == 0 {
+		return nil, nil, errors.New("no film stats found")
+	}
+	// The link text is abbreviated, like '312K', so use the tooltip
+	count := func(class string) int {
+		m := statCountRegex.FindString(stats.Find("li."+class+" a").First().AttrOr("title", ""))
+		count, _ := strconv.Atoi(strings.ReplaceAll(m, ",", ""))
+		return count
+	}
+	return &FilmStatistics{
+		Watches: count("filmstat-watches"),
+		Likes:   count("filmstat-likes"),
+		Lists:   count("filmstat-lists"),
+	}, nil, nil
+}
+
+// Statistics returns how many members watched and liked a film, and how many
+// lists it is in, from the stats that the film page loads separately
+func (f *FilmServiceOp) Statistics(ctx context.Context, slug string) (*FilmStatistics, error) {
+	slug, err := SlugFromURL(slug)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/csi/film/%s/stats/", f.client.baseURL, slug), nil)
+	if err != nil {
+		return nil, err
+	}
+	item, resp, err := f.client.sendRequest(req, ExtractFilmStatistics)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Response != nil {
+		dclose(resp.Body)
+	}
+	stats, ok := item.Data.(*FilmStatistics)
+	if !ok {
+		return nil, errors.New("unexpected data type for film statistics")
+	}
+	return stats, nil
+}
+
+// ExtractReleases returns the releases from the releases tab of a film page
+func ExtractReleases(r io.Reader) (interface{}, *Pagination, error) {
+	doc, err := newDocumentFromReader(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	return releasesWithDoc(doc), nil, nil
+}
+
+// Releases returns the dated releases of a film in each country, like
+// theatrical, digital and physical releases. Empty if Letterboxd lists none
+func (f *FilmServiceOp) Releases(ctx context.Context, slug string) ([]Release, error) {
+	slug, err := SlugFromURL(slug)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/film/%s/releases/", f.client.baseURL, slug), nil)
+	if err != nil {
+		return nil, err
+	}
+	item, resp, err := f.client.sendRequest(req, ExtractReleases)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Response != nil {
+		dclose(resp.Body)
+	}
+	releases, ok := item.Data.([]Release)
+	if !ok {
+		return nil, errors.New("unexpected data type for releases")
+	}
+	return releases, nil
+}
+
+// ExtractFilmLists returns the lists from a page of list summaries, like the
+// lists that include a film, or the lists a member liked
+func ExtractFilmLists(r io.Reader) (interface{}, *Pagination, error) {
+	doc, err := newDocumentFromReader(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	lists := []*List{}
+	doc.Find("section.list").Each(func(i int, s *goquery.Selection) {
+		title := s.Find("h2 a").First()
+		// Links look like /karsten/list/j-horror-essentials/
+		parts := strings.Split(strings.Trim(title.AttrOr("href", ""), "/"), "/")
+		if len(parts) != 3 || parts[1] != "list" {
+			return
+		}
+		lists = append(lists, &List{
+			Title: strings.TrimSpace(title.Text()),
+			Owner: stringOr(s.AttrOr("data-person", ""), parts[0]),
+			Slug:  parts[2],
+			// Only public lists are shown to other members
+			Visibility: ListVisibilityPublic,
+		})
+	})
+	return lists, paginationOrSinglePage(doc), nil
+}
+
+// WatchedBy returns the usernames of members who rated a film at or above
+// minRating, highest ratings first. minRating is in stars, from 0.5 to 5. Use a
+// limit of 0 or less to get as many as possible
+func (f *FilmServiceOp) WatchedBy(ctx context.Context, slug string, minRating float64, limit int) ([]string, error) {
+	slug, err := SlugFromURL(slug)
+	if err != nil {
+		return nil, err
+	}
+	minHalfStars := minRating * 2
+	if minHalfStars != math.Trunc(minHalfStars) || minHalfStars <= MinRating || minHalfStars > MaxRating {
+		return nil, fmt.Errorf("invalid minimum rating %v, must be between 0.5 and 5 in half stars", minRating)
+	}
+	members := []string{}
+	for rating := MaxRating; float64(rating) >= minHalfStars; rating-- {
+		stars := strconv.FormatFloat(float64(rating)/2, 'f', -1, 64)
+		for page := 1; page <= maxPages; page++ {
+			req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/film/%s/members/rated/%s/page/%d/", f.client.baseURL, slug, stars, page), nil)
+			if err != nil {
+				return nil, err
+			}
+			items, resp, err := f.client.sendRequest(req, ExtractMembers)
+			if err != nil {
+				return nil, err
+			}
+			if resp.Response != nil {
+				dclose(resp.Body)
+			}
+			members = append(members, items.Data.([]string)...)
+			if limit > 0 && len(members) >= limit {
+				return members[:limit], nil
+			}
+			if items.Pagination.IsLast {
+				break
+			}
+		}
+	}
+	return members, nil
+}
+
+// ExtractMembers returns the usernames from a film members page from an
+// io.Reader
+func ExtractMembers(r io.Reader) (interface{}, *Pagination, error) {
+	doc, err := newDocumentFromReader(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	members := []string{}
+	doc.Find("td.table-person").Each(func(i int, s *goquery.Selection) {
+		name := strings.Trim(s.Find("a.name").AttrOr("href", ""), "/")
+		if name != "" {
+			members = append(members, name)
+		}
+	})
+	pagination := paginationOrSinglePage(doc)
+	return members, pagination, nil
 }
 
 // NewFilm initializes a new Film pointer
@@ -325,9 +1076,75 @@ func NewFilm() *Film {
 	}
 }
 
+// filmJSON is the structured film data served from /film/{slug}/json/
+type filmJSON struct {
+	Result      bool   `json:"result"`
+	ID          int    `json:"id"`
+	Slug        string `json:"slug"`
+	Name        string `json:"name"`
+	ReleaseYear int    `json:"releaseYear"`
+	RunTime     int    `json:"runTime"`
+	URL         string `json:"url"`
+	Image125    string `json:"image125"`
+	Image150    string `json:"image150"`
+}
+
+// filmWithJSON gets a film from the JSON endpoint
+func (f *FilmServiceOp) filmWithJSON(ctx context.Context, slug string) (*Film, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/film/%s/json/", f.client.baseURL, slug), nil)
+	if err != nil {
+		return nil, err
+	}
+	item, resp, err := f.client.sendRequest(req, extractFilmFromJSON)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Response != nil {
+		dclose(resp.Body)
+	}
+	film, ok := item.Data.(*Film)
+	if !ok {
+		return nil, errors.New("unexpected data type for film json")
+	}
+	return film, nil
+}
+
+// extractFilmFromJSON returns a Film from the film JSON endpoint. This only has
+// the basics, so ExternalIDs are left nil
+func extractFilmFromJSON(r io.Reader) (interface{}, *Pagination, error) {
+	var data filmJSON
+	if err := json.NewDecoder(r).Decode(&data); err != nil {
+		return nil, nil, fmt.Errorf("could not decode film json: %w", err)
+	}
+	if !data.Result || data.Slug == "" {
+		return nil, nil, errors.New("film json did not include a film")
+	}
+	f := &Film{
+		Title:      data.Name,
+		Slug:       data.Slug,
+		Target:     data.URL,
+		Year:       data.ReleaseYear,
+		Runtime:    data.RunTime,
+		IsShort:    data.RunTime > 0 && data.RunTime <= shortFilmMaxMinutes,
+		PosterURL:  data.Image150,
+		PosterURLs: posterURLsWith(data.Image125, data.Image150),
+		HasPoster:  hasPoster(data.Image150),
+	}
+	if data.ID != 0 {
+		f.ID = strconv.Itoa(data.ID)
+	}
+	return f, nil, nil
+}
+
 func extractFilmFromFilmPage(r io.Reader) (interface{}, *Pagination, error) {
 	f := NewFilm()
-	doc := mustNewDocumentFromReader(r)
+	doc, err := newDocumentFromReader(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	if doc.Find("section.age-gate").Length() > 0 {
+		return nil, nil, ErrAgeGated
+	}
 	doc.Find("meta").Each(func(i int, s *goquery.Selection) {
 		var err error
 		if val, ok := s.Attr("property"); ok && val == "og:title" {
@@ -335,6 +1152,8 @@ func extractFilmFromFilmPage(r io.Reader) (interface{}, *Pagination, error) {
 			f.Year, err = extractYearFromTitle(fullTitle)
 			if err == nil {
 				f.Title = fullTitle[0 : len(fullTitle)-7]
+			} else {
+				f.YearUnknown = true
 			}
 		}
 	})
@@ -354,9 +1173,245 @@ func extractFilmFromFilmPage(r io.Reader) (interface{}, *Pagination, error) {
 		//}
 	})
 	f.ExternalIDs = externalIDsWithDoc(doc)
+	f.PosterURL = posterURLWithDoc(doc)
+	f.HasPoster = hasPoster(f.PosterURL)
+	f.PosterURLs = posterURLsWith(f.PosterURL)
+	f.PosterWidth, f.PosterHeight, f.PosterColor = posterMetadataWithSelection(doc.Find("div").Find("div").Find(".poster").First())
+	f.BackdropURL = doc.Find("#backdrop").AttrOr("data-backdrop", "")
+	f.TrailerURL = trailerURLWithDoc(doc)
+	f.Releases = releasesWithDoc(doc)
+	f.Themes = themesWithDoc(doc)
+	f.Credits = creditsWithDoc(doc)
+	f.Genres = sluglistWithDoc(doc, "#tab-genres", "/films/genre/")
+	f.Countries = sluglistWithDoc(doc, "#tab-details", "/films/country/")
+	f.Languages = sluglistWithDoc(doc, "#tab-details", "/films/language/")
+	f.AlternativeTitles = alternativeTitlesWithDoc(doc)
+	f.Runtime = runtimeWithDoc(doc)
+	f.AverageRating, f.RatingCount = ratingsWithDoc(doc)
+	f.IsShort = f.Runtime > 0 && f.Runtime <= shortFilmMaxMinutes
+	f.FriendsWatchedCount, f.FriendsLikedCount = friendsActivityWithDoc(doc)
 	return f, nil, nil
 }
 
+// releasesWithDoc returns the releases from the releases tab of a film page,
+// one per country
+func releasesWithDoc(doc *goquery.Document) []Release {
+	var releases []Release
+	doc.Find("#tab-releases section.release-table").Each(func(i int, section *goquery.Selection) {
+		releaseType := strings.TrimSpace(section.Find("h3.release-table-title").First().Text())
+		section.Find("div.listitem").Each(func(i int, item *goquery.Selection) {
+			date, err := time.Parse("2 Jan 2006", strings.TrimSpace(item.Find("h5.date").Text()))
+			if err != nil {
+				return
+			}
+			item.Find("li.release-country").Each(func(i int, country *goquery.Selection) {
+				releases = append(releases, Release{
+					Type:    releaseType,
+					Country: strings.TrimSpace(country.Find("span.name").Text()),
+					Date:    date,
+				})
+			})
+		})
+	})
+	return releases
+}
+
+// runtimeWithDoc returns the runtime in minutes from the footer of a film page,
+// or 0 when it is not listed
+func runtimeWithDoc(doc *goquery.Document) int {
+	m := runtimeRegex.FindStringSubmatch(doc.Find("p.text-link.text-footer").First().Text())
+	if m == nil {
+		return 0
+	}
+	runtime, err := strconv.Atoi(strings.ReplaceAll(m[1], ",", ""))
+	if err != nil {
+		return 0
+	}
+	return runtime
+}
+
+// friendsActivityWithDoc returns how many friends watched and liked the film,
+// from the friends activity summary on a signed in film page
+func friendsActivityWithDoc(doc *goquery.Document) (watched, liked int) {
+	summary := doc.Find("section.activity-from-friends p.activity-summary")
+	return countWithText(summary.Find("a.icon-watched").First().Text()), countWithText(summary.Find("a.icon-liked").First().Text())
+}
+
+// countWithText returns the leading count in text like '1,204 watched', or 0
+func countWithText(text string) int {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return 0
+	}
+	count, err := strconv.Atoi(strings.ReplaceAll(fields[0], ",", ""))
+	if err != nil {
+		return 0
+	}
+	return count
+}
+
+// themesWithDoc returns the names of the themes and mini themes (nanogenres)
+// from the genres tab of a film page
+func themesWithDoc(doc *goquery.Document) []string {
+	var themes []string
+	doc.Find("#tab-genres a.text-slug").Each(func(i int, s *goquery.Selection) {
+		href := s.AttrOr("href", "")
+		if strings.HasPrefix(href, "/films/theme/") || strings.HasPrefix(href, "/films/mini-theme/") {
+			themes = append(themes, strings.TrimSpace(s.Text()))
+		}
+	})
+	return themes
+}
+
+// sluglistWithDoc returns the unique names linked from a film page tab, for the
+// links under hrefPrefix, like /films/genre/
+func sluglistWithDoc(doc *goquery.Document, tab, hrefPrefix string) []string {
+	var names []string
+	doc.Find(tab + " a.text-slug").Each(func(i int, s *goquery.Selection) {
+		if !strings.HasPrefix(s.AttrOr("href", ""), hrefPrefix) {
+			return
+		}
+		if name := strings.TrimSpace(s.Text()); !stringInSlice(name, names) {
+			names = append(names, name)
+		}
+	})
+	return names
+}
+
+// alternativeTitlesWithDoc returns the alternative titles from the details tab
+// of a film page. Letterboxd lists them all in one comma separated paragraph,
+// with no markup between titles, so a title that itself has a ", " in it, like
+// "Crouching Tiger, Hidden Dragon", comes back split in to pieces
+func alternativeTitlesWithDoc(doc *goquery.Document) []string {
+	var titles []string
+	doc.Find("#tab-details h3").Each(func(i int, s *goquery.Selection) {
+		if !strings.HasPrefix(strings.TrimSpace(s.Text()), "Alternative Title") {
+			return
+		}
+		for _, title := range strings.Split(s.Next().Find("p").First().Text(), ", ") {
+			if title = strings.TrimSpace(title); title != "" {
+				titles = append(titles, title)
+			}
+		}
+	})
+	return titles
+}
+
+// creditsWithDoc returns the cast and crew from the tabs of a film page, or nil
+// if the page has neither. Crew jobs come from the person links, like
+// /director/melvin-van-peebles/
+func creditsWithDoc(doc *goquery.Document) *Credits {
+	credits := &Credits{}
+	doc.Find("#tab-cast a.text-slug").Each(func(i int, s *goquery.Selection) {
+		parts := strings.Split(strings.Trim(s.AttrOr("href", ""), "/"), "/")
+		// Skips the link that shows the rest of the cast
+		if len(parts) != 2 || parts[0] != "actor" {
+			return
+		}
+		credits.Cast = append(credits.Cast, CastMember{
+			Name:      strings.TrimSpace(s.Text()),
+			Slug:      parts[1],
+			Character: strings.TrimSpace(s.AttrOr("title", "")),
+		})
+	})
+	doc.Find("#tab-crew a.text-slug").Each(func(i int, s *goquery.Selection) {
+		parts := strings.Split(strings.Trim(s.AttrOr("href", ""), "/"), "/")
+		if len(parts) != 2 {
+			return
+		}
+		credits.Crew = append(credits.Crew, CrewMember{
+			Name: strings.TrimSpace(s.Text()),
+			Slug: parts[1],
+			Job:  parts[0],
+		})
+	})
+	if credits.Cast == nil && credits.Crew == nil {
+		return nil
+	}
+	return credits
+}
+
+// posterURLWithDoc returns the poster from the structured data on a film
+// page. The poster element itself is lazy loaded, so only has a placeholder
+func posterURLWithDoc(doc *goquery.Document) string {
+	var data struct {
+		Image string `json:"image"`
+	}
+	if err := jsonLDWithDoc(doc, &data); err != nil {
+		return ""
+	}
+	return data.Image
+}
+
+// hasPoster returns true if posterURL is a real poster, and not the
+// placeholder shown for films without one, like
+// https://s.ltrbxd.com/static/img/empty-poster-230.c6baa486.png
+func hasPoster(posterURL string) bool {
+	u, err := url.Parse(posterURL)
+	if err != nil || u.Path == "" {
+		return false
+	}
+	return !strings.HasPrefix(path.Base(u.Path), "empty-poster-")
+}
+
+// posterURLsWith returns the real posters in posterURLs keyed by their width,
+// or nil if there are none
+func posterURLsWith(posterURLs ...string) map[int]string {
+	var ret map[int]string
+	for _, posterURL := range posterURLs {
+		if !hasPoster(posterURL) {
+			continue
+		}
+		m := posterSizeRegex.FindStringSubmatch(posterURL)
+		if m == nil {
+			continue
+		}
+		width, err := strconv.Atoi(m[1])
+		if err != nil {
+			continue
+		}
+		if ret == nil {
+			ret = map[int]string{}
+		}
+		ret[width] = posterURL
+	}
+	return ret
+}
+
+// ratingsWithDoc returns the site average rating, out of 5, and how many
+// members rated the film, from the JSON-LD of a film page. Both are 0 when the
+// film doesn't have enough ratings for an average yet
+func ratingsWithDoc(doc *goquery.Document) (average float64, count int) {
+	var data struct {
+		AggregateRating struct {
+			RatingValue float64 `json:"ratingValue"`
+			RatingCount int     `json:"ratingCount"`
+		} `json:"aggregateRating"`
+	}
+	if err := jsonLDWithDoc(doc, &data); err != nil {
+		return 0, 0
+	}
+	return data.AggregateRating.RatingValue, data.AggregateRating.RatingCount
+}
+
+// jsonLDWithDoc unmarshals the JSON-LD block of a film page in to v
+func jsonLDWithDoc(doc *goquery.Document, v interface{}) error {
+	raw := doc.Find(`script[type="application/ld+json"]`).First().Text()
+	raw = strings.TrimSpace(strings.NewReplacer("/* <![CDATA[ */", "", "/* ]]> */", "").Replace(raw))
+	return json.Unmarshal([]byte(raw), v)
+}
+
+// trailerURLWithDoc returns the trailer link from a film page, or an empty
+// string if the film has no trailer. Protocol relative links are returned as
+// https
+func trailerURLWithDoc(doc *goquery.Document) string {
+	href := doc.Find(`a[data-track-action="Trailer"]`).First().AttrOr("href", "")
+	if strings.HasPrefix(href, "//") {
+		return "https:" + href
+	}
+	return href
+}
+
 func externalIDsWithDoc(doc *goquery.Document) *ExternalFilmIDs {
 	e := &ExternalFilmIDs{}
 	doc.Find("a").Each(func(i int, s *goquery.Selection) {
@@ -382,15 +1437,21 @@ func extractIDFromURL(url string) string {
 func previewsWithDoc(doc *goquery.Document) FilmSet {
 	var previews FilmSet
 	doc.Find("li.poster-container").Each(func(i int, s *goquery.Selection) {
+		average := averageRatingWithPoster(s)
 		s.Find("div").Each(func(i int, s *goquery.Selection) {
 			if s.HasClass("film-poster") {
 				f := Film{}
 				f.ID = s.AttrOr("data-film-id", "")
 				f.Slug = normalizeSlug(s.AttrOr("data-film-slug", ""))
 				f.Target = s.AttrOr("data-target-link", "")
+				f.PosterWidth, f.PosterHeight, f.PosterColor = posterMetadataWithSelection(s)
 				s.Find("img.image").Each(func(i int, s *goquery.Selection) {
 					f.Title = s.AttrOr("alt", "")
 				})
+				f.AverageRating = average
+				if viewing := s.Parent().Find("p.poster-viewingdata span.rating"); viewing.Length() > 0 {
+					f.UserRating = ratingWithSelection(viewing.First())
+				}
 				previews = append(previews, &f)
 			}
 		})
@@ -398,8 +1459,34 @@ func previewsWithDoc(doc *goquery.Document) FilmSet {
 	return previews
 }
 
+// posterMetadataWithSelection returns the dimensions and average color a
+// poster div carries, when Letterboxd includes them
+func posterMetadataWithSelection(s *goquery.Selection) (width, height int, color string) {
+	width, _ = strconv.Atoi(s.AttrOr("data-image-width", ""))
+	height, _ = strconv.Atoi(s.AttrOr("data-image-height", ""))
+	return width, height, s.AttrOr("data-average-color", "")
+}
+
+// averageRatingWithPoster returns the site average rating from a poster
+// container. Browse pages put it in a data attribute, with the poster tooltip
+// as a fallback. Returns 0 if neither is present
+func averageRatingWithPoster(s *goquery.Selection) float64 {
+	if val, ok := s.Attr("data-average-rating"); ok {
+		if avg, err := strconv.ParseFloat(val, 64); err == nil {
+			return avg
+		}
+	}
+	fields := strings.Fields(s.Find(".frame").AttrOr("data-original-title", ""))
+	if len(fields) > 0 {
+		if avg, err := strconv.ParseFloat(fields[len(fields)-1], 64); err == nil {
+			return avg
+		}
+	}
+	return 0
+}
+
 func extractFilmography(r io.Reader) (interface{}, *Pagination, error) {
-	doc, err := goquery.NewDocumentFromReader(r)
+	doc, err := newDocumentFromReader(r)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -415,12 +1502,13 @@ func GetFilmographyProfessions() []string {
 // GetWatchedIMDBIDs returns a list of imdb ids that have been watched by a given user
 func (f *FilmServiceOp) GetWatchedIMDBIDs(ctx context.Context, username string) ([]string, error) {
 	wfilmC := make(chan *Film)
-	wdoneC := make(chan error)
+	// Buffered so the stream can finish up even if we have already returned
+	wdoneC := make(chan error, 1)
 
 	go f.client.User.StreamWatched(ctx, username, wfilmC, wdoneC)
 
 	var watchedIDs []string
-	for loop := true; loop; {
+	for {
 		select {
 		case film := <-wfilmC:
 			if film.ExternalIDs != nil {
@@ -428,13 +1516,13 @@ func (f *FilmServiceOp) GetWatchedIMDBIDs(ctx context.Context, username string)
 			}
 		case err := <-wdoneC:
 			if err != nil {
-				wdoneC <- err
-			} else {
-				loop = false
+				return nil, err
 			}
+			return watchedIDs, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
 		}
 	}
-	return watchedIDs, nil
 }
 
 // SlurpFilms Helper blocking function to slurp a batch of films from the
@@ -456,6 +1544,45 @@ func SlurpFilms(filmC chan *Film, errorC chan error) (FilmSet, error) {
 	return ret, nil
 }
 
+// SlurpFilmsN is like SlurpFilms, but returns as soon as n films have been
+// collected. The producer is then stopped using cancel, which should cancel the
+// context passed to the streaming call. SlurpFilmsN waits for the producer to
+// finish before returning, so no goroutines are left behind
+func SlurpFilmsN(cancel context.CancelFunc, filmC chan *Film, errorC chan error, n int) (FilmSet, error) {
+	defer cancel()
+	var ret FilmSet
+	for len(ret) < n {
+		select {
+		case film := <-filmC:
+			ret = append(ret, film)
+		case err := <-errorC:
+			if err != nil {
+				return nil, err
+			}
+			return ret, nil
+		}
+	}
+	cancel()
+	// Drain anything the producer sends while it notices the cancellation
+	for {
+		select {
+		case <-filmC:
+		case <-errorC:
+			return ret, nil
+		}
+	}
+}
+
+// sendFilm sends a film down rchan, giving up if the context is done first
+func sendFilm(ctx context.Context, rchan chan *Film, film *Film) error {
+	select {
+	case rchan <- film:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 func extractYearFromTitle(title string) (int, error) {
 	var year int
 	var err error
@@ -501,3 +1628,95 @@ func (fs *FilmSet) TMDBIDs() []string {
 	}
 	return ids
 }
+
+// TotalRuntime returns the combined runtime of the films in a FilmSet. Films
+// with an unknown runtime are skipped
+func (fs *FilmSet) TotalRuntime() time.Duration {
+	var total time.Duration
+	for _, item := range *fs {
+		if item == nil || item.Runtime <= 0 {
+			continue
+		}
+		total += time.Duration(item.Runtime) * time.Minute
+	}
+	return total
+}
+
+// AverageRuntime returns the average runtime of the films in a FilmSet,
+// skipping films with an unknown runtime. Returns 0 if no runtimes are known
+func (fs *FilmSet) AverageRuntime() time.Duration {
+	var count int
+	for _, item := range *fs {
+		if item != nil && item.Runtime > 0 {
+			count++
+		}
+	}
+	if count == 0 {
+		return 0
+	}
+	return fs.TotalRuntime() / time.Duration(count)
+}
+
+// BySlug returns a map of the films in a FilmSet, keyed by slug. Films without
+// a slug are skipped, and the last film wins if a slug appears twice
+func (fs *FilmSet) BySlug() map[string]*Film {
+	ret := make(map[string]*Film, len(*fs))
+	for _, item := range *fs {
+		if item == nil || item.Slug == "" {
+			continue
+		}
+		ret[item.Slug] = item
+	}
+	return ret
+}
+
+// ByIMDB returns a map of the films in a FilmSet, keyed by IMDB ID. Films
+// without an IMDB ID are skipped, and the last film wins if an ID appears twice
+func (fs *FilmSet) ByIMDB() map[string]*Film {
+	ret := make(map[string]*Film, len(*fs))
+	for _, item := range *fs {
+		if item == nil || item.ExternalIDs == nil || item.ExternalIDs.IMDB == "" {
+			continue
+		}
+		ret[item.ExternalIDs.IMDB] = item
+	}
+	return ret
+}
+
+// WriteJSONL writes the films in a FilmSet to w as JSON Lines, one film per
+// line. Nil films are skipped
+func (fs *FilmSet) WriteJSONL(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	for _, item := range *fs {
+		if item == nil {
+			continue
+		}
+		if err := enc.Encode(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteFilmsJSONL writes films to w as JSON Lines as they come in from a
+// stream, instead of collecting them first like SlurpFilms. If writing fails,
+// the rest of the stream is drained so the producer can finish, and the write
+// error is returned
+func WriteFilmsJSONL(w io.Writer, filmC chan *Film, errorC chan error) error {
+	enc := json.NewEncoder(w)
+	var writeErr error
+	for {
+		select {
+		case film := <-filmC:
+			if writeErr != nil || film == nil {
+				continue
+			}
+			writeErr = enc.Encode(film)
+		case err := <-errorC:
+			if err != nil {
+				return err
+			}
+			return writeErr
+		}
+	}
+}
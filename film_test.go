@@ -1,11 +1,18 @@
 package letterboxd
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
 	"os"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/go-redis/cache/v8"
 	"github.com/stretchr/testify/require"
 )
 
@@ -40,6 +47,348 @@ func TestExtractFilmFromFilmPage(t *testing.T) {
 	require.Equal(t, "sweet-sweetbacks-baadasssss-song", film.Slug)
 	require.Equal(t, "/film/sweet-sweetbacks-baadasssss-song/", film.Target)
 	require.Equal(t, "48640", film.ID)
+	require.Equal(t, "https://a.ltrbxd.com/resized/sm/upload/04/l0/gk/po/sweet%20sweetback-1200-1200-675-675-crop-000000.jpg?k=baa1aa9ac5", film.BackdropURL)
+	require.Equal(t, "https://a.ltrbxd.com/resized/film-poster/4/8/6/4/0/48640-sweet-sweetback-s-baadasssss-song-0-230-0-345-crop.jpg?k=ce9664b301", film.PosterURL)
+	require.Equal(t, 3.21, film.AverageRating)
+	require.Equal(t, 5914, film.RatingCount)
+}
+
+func TestExtractFilmFromFilmPageNoBackdrop(t *testing.T) {
+	f, err := os.Open("testdata/film/missing-year.html")
+	require.NoError(t, err)
+	defer f.Close()
+	i, _, err := extractFilmFromFilmPage(f)
+	require.NoError(t, err)
+	require.Equal(t, "", i.(*Film).BackdropURL)
+}
+
+func TestExtractFilmFromFilmPageTrailer(t *testing.T) {
+	f, err := os.Open("testdata/film/pulse.html")
+	require.NoError(t, err)
+	defer f.Close()
+	i, _, err := extractFilmFromFilmPage(f)
+	require.NoError(t, err)
+	film := i.(*Film)
+	require.Equal(t, "pulse", film.Slug)
+	require.Equal(t, "https://www.youtube.com/embed/V6f6ZxIvIbs?rel=0&wmode=transparent", film.TrailerURL)
+}
+
+func TestExtractFilmFromFilmPageShort(t *testing.T) {
+	f, err := os.Open("testdata/film/la-jetee.html")
+	require.NoError(t, err)
+	defer f.Close()
+	i, _, err := extractFilmFromFilmPage(f)
+	require.NoError(t, err)
+	film := i.(*Film)
+	require.Equal(t, "la-jetee", film.Slug)
+	require.Equal(t, 1962, film.Year)
+	require.Equal(t, 28, film.Runtime)
+	require.True(t, film.IsShort)
+	// No ratings to average on this page
+	require.Equal(t, 0.0, film.AverageRating)
+}
+
+func TestExtractFilmFromFilmPageFeature(t *testing.T) {
+	f, err := os.Open("testdata/film/sweetback.html")
+	require.NoError(t, err)
+	defer f.Close()
+	i, _, err := extractFilmFromFilmPage(f)
+	require.NoError(t, err)
+	require.Equal(t, 97, i.(*Film).Runtime)
+	require.False(t, i.(*Film).IsShort)
+}
+
+func TestExtractFilmFromFilmPageFriends(t *testing.T) {
+	f, err := os.Open("testdata/film/sweetback-signed-in.html")
+	require.NoError(t, err)
+	defer f.Close()
+	i, _, err := extractFilmFromFilmPage(f)
+	require.NoError(t, err)
+	film := i.(*Film)
+	require.Equal(t, "48640", film.ID)
+	require.Equal(t, 12, film.FriendsWatchedCount)
+	require.Equal(t, 3, film.FriendsLikedCount)
+
+	// Signed out pages have no friends activity
+	f, err = os.Open("testdata/film/sweetback.html")
+	require.NoError(t, err)
+	defer f.Close()
+	i, _, err = extractFilmFromFilmPage(f)
+	require.NoError(t, err)
+	require.Equal(t, 0, i.(*Film).FriendsWatchedCount)
+	require.Equal(t, 0, i.(*Film).FriendsLikedCount)
+}
+
+func TestExtractFilmFromFilmPagePosterMetadata(t *testing.T) {
+	f, err := os.Open("testdata/film/poster-color.html")
+	require.NoError(t, err)
+	defer f.Close()
+	i, _, err := extractFilmFromFilmPage(f)
+	require.NoError(t, err)
+	film := i.(*Film)
+	// The hero poster, not the related films after it
+	require.Equal(t, 230, film.PosterWidth)
+	require.Equal(t, 345, film.PosterHeight)
+	require.Equal(t, "#2b3a4f", film.PosterColor)
+
+	f, err = os.Open("testdata/film/no-poster.html")
+	require.NoError(t, err)
+	defer f.Close()
+	i, _, err = extractFilmFromFilmPage(f)
+	require.NoError(t, err)
+	film = i.(*Film)
+	require.Equal(t, 230, film.PosterWidth)
+	require.Equal(t, "", film.PosterColor)
+}
+
+func TestExtractFilmFromFilmPageNoPoster(t *testing.T) {
+	f, err := os.Open("testdata/film/no-poster.html")
+	require.NoError(t, err)
+	defer f.Close()
+	i, _, err := extractFilmFromFilmPage(f)
+	require.NoError(t, err)
+	film := i.(*Film)
+	require.Equal(t, "the-lost-reel", film.Slug)
+	require.Equal(t, "https://s.ltrbxd.com/static/img/empty-poster-230.c6baa486.png", film.PosterURL)
+	require.False(t, film.HasPoster)
+
+	f, err = os.Open("testdata/film/sweetback.html")
+	require.NoError(t, err)
+	defer f.Close()
+	i, _, err = extractFilmFromFilmPage(f)
+	require.NoError(t, err)
+	require.True(t, i.(*Film).HasPoster)
+}
+
+func TestHasPoster(t *testing.T) {
+	require.True(t, hasPoster("https://a.ltrbxd.com/resized/film-poster/4/8/6/4/0/48640-sweet-sweetback-s-baadasssss-song-0-230-0-345-crop.jpg?k=ce9664b301"))
+	require.True(t, hasPoster("/resized/film-poster/1/2/3/123-0-150-0-225-crop.jpg"))
+	require.False(t, hasPoster("https://s.ltrbxd.com/static/img/empty-poster-150.7c5f9a6c.png"))
+	require.False(t, hasPoster(""))
+}
+
+func TestRuntimeWithDoc(t *testing.T) {
+	tests := map[string]struct {
+		html string
+		want int
+	}{
+		"feature": {html: `<p class="text-link text-footer">97&nbsp;mins &nbsp; More at</p>`, want: 97},
+		"single":  {html: `<p class="text-link text-footer">1&nbsp;min &nbsp; More at</p>`, want: 1},
+		"long":    {html: `<p class="text-link text-footer">1,200&nbsp;mins</p>`, want: 1200},
+		"missing": {html: `<p class="text-link text-footer">More at</p>`, want: 0},
+	}
+	for desc, tt := range tests {
+		doc := mustNewDocumentFromReader(strings.NewReader(tt.html))
+		require.Equal(t, tt.want, runtimeWithDoc(doc), desc)
+	}
+}
+
+func TestExtractFilmFromFilmPageReleases(t *testing.T) {
+	f, err := os.Open("testdata/film/pulse.html")
+	require.NoError(t, err)
+	defer f.Close()
+	i, _, err := extractFilmFromFilmPage(f)
+	require.NoError(t, err)
+	film := i.(*Film)
+	mustDate := func(s string) time.Time {
+		d, err := time.Parse("2006-01-02", s)
+		require.NoError(t, err)
+		return d
+	}
+	require.Equal(t, []Release{
+		{Type: "Premiere", Country: "France", Date: mustDate("2001-05-13")},
+		{Type: "Theatrical", Country: "Japan", Date: mustDate("2001-02-03")},
+		{Type: "Theatrical", Country: "USA", Date: mustDate("2005-11-11")},
+		{Type: "Theatrical", Country: "Canada", Date: mustDate("2005-11-11")},
+		{Type: "Physical", Country: "USA", Date: mustDate("2006-03-01")},
+	}, film.Releases)
+
+	// No releases tab
+	sf, err := os.Open("testdata/film/sweetback.html")
+	require.NoError(t, err)
+	defer sf.Close()
+	i, _, err = extractFilmFromFilmPage(sf)
+	require.NoError(t, err)
+	require.Empty(t, i.(*Film).Releases)
+}
+
+func TestExtractFilmFromFilmPageThemes(t *testing.T) {
+	f, err := os.Open("testdata/film/sweetback.html")
+	require.NoError(t, err)
+	defer f.Close()
+	i, _, err := extractFilmFromFilmPage(f)
+	require.NoError(t, err)
+	themes := i.(*Film).Themes
+	require.Equal(t, 8, len(themes))
+	require.Equal(t, "Politics and human rights", themes[0])
+	require.Equal(t, "drugs, violence, crime, gritty or cops", themes[3])
+	require.NotContains(t, themes, "crime")
+	require.NotContains(t, themes, "Show All…")
+
+	// No themes shown
+	lf, err := os.Open("testdata/film/la-jetee.html")
+	require.NoError(t, err)
+	defer lf.Close()
+	i, _, err = extractFilmFromFilmPage(lf)
+	require.NoError(t, err)
+	require.Empty(t, i.(*Film).Themes)
+}
+
+func TestExtractFilmFromFilmPageDetails(t *testing.T) {
+	f, err := os.Open("testdata/film/sweetback.html")
+	require.NoError(t, err)
+	defer f.Close()
+	i, _, err := extractFilmFromFilmPage(f)
+	require.NoError(t, err)
+	film := i.(*Film)
+	require.Equal(t, []string{"crime", "drama", "action"}, film.Genres)
+	require.Equal(t, []string{"USA"}, film.Countries)
+	require.Equal(t, []string{"English"}, film.Languages)
+
+	// Primary and spoken languages are only listed once
+	i, _, err = extractFilmFromFilmPage(strings.NewReader(`<div id="tab-details">
+<h3><span>Primary Language</span></h3><div class="text-sluglist"><p><a href="/films/language/french/" class="text-slug">French</a></p></div>
+<h3><span>Spoken Languages</span></h3><div class="text-sluglist"><p><a href="/films/language/french/" class="text-slug">French</a> <a href="/films/language/german/" class="text-slug">German</a></p></div>
+</div>`))
+	require.NoError(t, err)
+	film = i.(*Film)
+	require.Equal(t, []string{"French", "German"}, film.Languages)
+	require.Empty(t, film.Genres)
+	require.Empty(t, film.Countries)
+}
+
+func TestExtractFilmFromFilmPageAlternativeTitles(t *testing.T) {
+	f, err := os.Open("testdata/film/sweetback.html")
+	require.NoError(t, err)
+	defer f.Close()
+	i, _, err := extractFilmFromFilmPage(f)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Sweet Sweetback's Badass Song"}, i.(*Film).AlternativeTitles)
+
+	i, _, err = extractFilmFromFilmPage(strings.NewReader(`<div id="tab-details">
+<h3><span>Alternative Titles</span></h3><div class="text-indentedlist"><p>
+	キュア, La cura, Der Killer mit dem X 
+</p></div></div>`))
+	require.NoError(t, err)
+	require.Equal(t, []string{"キュア", "La cura", "Der Killer mit dem X"}, i.(*Film).AlternativeTitles)
+
+	// None listed
+	lf, err := os.Open("testdata/film/la-jetee.html")
+	require.NoError(t, err)
+	defer lf.Close()
+	i, _, err = extractFilmFromFilmPage(lf)
+	require.NoError(t, err)
+	require.Empty(t, i.(*Film).AlternativeTitles)
+}
+
+func TestExtractFilmFromFilmPageCredits(t *testing.T) {
+	f, err := os.Open("testdata/film/sweetback.html")
+	require.NoError(t, err)
+	defer f.Close()
+	i, _, err := extractFilmFromFilmPage(f)
+	require.NoError(t, err)
+	credits := i.(*Film).Credits
+	require.NotNil(t, credits)
+	require.Equal(t, 16, len(credits.Cast))
+	require.Equal(t, CastMember{Name: "Melvin Van Peebles", Slug: "melvin-van-peebles", Character: "Sweetback"}, credits.Cast[1])
+	require.Equal(t, "Chesley Noone", credits.Cast[13].Name)
+	require.Equal(t, "", credits.Cast[13].Character)
+	require.Equal(t, 7, len(credits.Crew))
+	require.Equal(t, []CrewMember{{Name: "Melvin Van Peebles", Slug: "melvin-van-peebles", Job: "director"}}, credits.CrewWithJob("director"))
+	require.Equal(t, 2, len(credits.CrewWithJob("producer")))
+	require.Equal(t, "Robert Maxwell", credits.CrewWithJob("cinematography")[0].Name)
+	require.Empty(t, credits.CrewWithJob("stunts"))
+
+	// No cast or crew shown
+	i, _, err = extractFilmFromFilmPage(strings.NewReader(`<html><body></body></html>`))
+	require.NoError(t, err)
+	require.Nil(t, i.(*Film).Credits)
+}
+
+func TestExtractFilmFromFilmPagePosterURLs(t *testing.T) {
+	f, err := os.Open("testdata/film/sweetback.html")
+	require.NoError(t, err)
+	defer f.Close()
+	i, _, err := extractFilmFromFilmPage(f)
+	require.NoError(t, err)
+	film := i.(*Film)
+	require.Equal(t, 97, film.Runtime)
+	require.Equal(t, map[int]string{230: film.PosterURL}, film.PosterURLs)
+
+	// Placeholders are not poster sizes
+	require.Nil(t, posterURLsWith("https://s.ltrbxd.com/static/img/empty-poster-230.c6baa486.png", ""))
+}
+
+func TestExtractFilmFromJSON(t *testing.T) {
+	f, err := os.Open("testdata/film/json/sweetback.json")
+	require.NoError(t, err)
+	defer f.Close()
+	i, _, err := extractFilmFromJSON(f)
+	require.NoError(t, err)
+	require.Equal(t, &Film{
+		ID:        "48640",
+		Title:     "Sweet Sweetback's Baadasssss Song",
+		Slug:      "sweet-sweetbacks-baadasssss-song",
+		Target:    "/film/sweet-sweetbacks-baadasssss-song/",
+		Year:      1971,
+		PosterURL: "https://a.ltrbxd.com/resized/film-poster/4/8/6/4/0/48640-sweet-sweetback-s-baadasssss-song-0-150-0-225-crop.jpg",
+		PosterURLs: map[int]string{
+			125: "https://a.ltrbxd.com/resized/film-poster/4/8/6/4/0/48640-sweet-sweetback-s-baadasssss-song-0-125-0-187-crop.jpg",
+			150: "https://a.ltrbxd.com/resized/film-poster/4/8/6/4/0/48640-sweet-sweetback-s-baadasssss-song-0-150-0-225-crop.jpg",
+		},
+		HasPoster: true,
+		Runtime:   97,
+	}, i.(*Film))
+
+	_, _, err = extractFilmFromJSON(strings.NewReader(`<html></html>`))
+	require.Error(t, err)
+	_, _, err = extractFilmFromJSON(strings.NewReader(`{"result":false}`))
+	require.Error(t, err)
+}
+
+func TestFilmGetWithFilmJSON(t *testing.T) {
+	tr := &countingTransport{prefix: "/film/"}
+	c := New(WithNoCache(), WithBaseURL(srv.URL), WithFilmJSON(true))
+	c.client.Transport = tr
+
+	film, err := c.Film.Get(context.TODO(), "sweet-sweetbacks-baadasssss-song")
+	require.NoError(t, err)
+	require.Equal(t, "48640", film.ID)
+	require.Equal(t, 1971, film.Year)
+	require.Nil(t, film.ExternalIDs)
+	require.Equal(t, "https://a.ltrbxd.com/resized/film-poster/4/8/6/4/0/48640-sweet-sweetback-s-baadasssss-song-0-150-0-225-crop.jpg", film.PosterURL)
+	require.Equal(t, 1, tr.count)
+
+	// No JSON for this one, so the film page is scraped instead
+	film, err = c.Film.Get(context.TODO(), "cure")
+	require.NoError(t, err)
+	require.Equal(t, "tt0067810", film.ExternalIDs.IMDB)
+	require.Equal(t, 3, tr.count)
+}
+
+func TestFilmGetWithFilmJSONCached(t *testing.T) {
+	tr := &countingTransport{prefix: "/film/"}
+	c := New(WithCache(cache.New(&cache.Options{
+		LocalCache: cache.NewTinyLFU(10, time.Minute),
+	})), WithBaseURL(srv.URL), WithFilmJSON(true))
+	c.client.Transport = tr
+
+	for i := 0; i < 2; i++ {
+		film, err := c.Film.Get(context.TODO(), "sweet-sweetbacks-baadasssss-song")
+		require.NoError(t, err)
+		require.Equal(t, "48640", film.ID)
+	}
+	require.Equal(t, 1, tr.count)
+	require.NotNil(t, filmWithCache(c.Cache, "/letterboxd/film-json/sweet-sweetbacks-baadasssss-song"))
+}
+
+func TestExtractFilmFromFilmPageNoTrailer(t *testing.T) {
+	f, err := os.Open("testdata/film/sweetback.html")
+	require.NoError(t, err)
+	defer f.Close()
+	i, _, err := extractFilmFromFilmPage(f)
+	require.NoError(t, err)
+	require.Equal(t, "", i.(*Film).TrailerURL)
 }
 
 func TestEnhanceFilmList(t *testing.T) {
@@ -89,6 +438,16 @@ func TestValidateFilmography(t *testing.T) {
 			Person:     "nicolas-cage",
 			Profession: "actor",
 		}, false},
+		{FilmographyOpt{
+			Person:     "nicolas-cage",
+			Profession: "actor",
+			PosterSize: "huge",
+		}, true},
+		{FilmographyOpt{
+			Person:     "nicolas-cage",
+			Profession: "actor",
+			PosterSize: "large",
+		}, false},
 	}
 	for _, tt := range tests {
 		got := tt.opt.Validate()
@@ -150,6 +509,37 @@ func TestExtractYearFromTitle(t *testing.T) {
 	}
 }
 
+func TestEnhanceFilmRenamedSlug(t *testing.T) {
+	film := &Film{
+		Slug:   "renamed-old",
+		Target: "/film/renamed-new/",
+	}
+	require.NoError(t, sc.Film.EnhanceFilm(context.TODO(), film))
+	require.Equal(t, "sweet-sweetbacks-baadasssss-song", film.Slug)
+	require.Equal(t, "tt0067810", film.ExternalIDs.IMDB)
+
+	// Nothing to fall back on
+	require.Error(t, sc.Film.EnhanceFilm(context.TODO(), &Film{Slug: "renamed-old"}))
+}
+
+func TestEnhanceFilmYearUnknown(t *testing.T) {
+	tr := &countingTransport{prefix: "/film/"}
+	var warnings bytes.Buffer
+	c := New(WithCache(cache.New(&cache.Options{
+		LocalCache: cache.NewTinyLFU(10, time.Minute),
+	})), WithBaseURL(srv.URL), WithWarnWriter(&warnings))
+	c.client.Transport = tr
+
+	for i := 0; i < 2; i++ {
+		film := &Film{Slug: "nightmare-city"}
+		require.NoError(t, c.Film.EnhanceFilm(context.TODO(), film))
+		require.Equal(t, 0, film.Year)
+		require.True(t, film.YearUnknown)
+	}
+	require.Equal(t, 1, tr.count)
+	require.Equal(t, "no year found for film: nightmare-city\n", warnings.String())
+}
+
 func TestEnhanceFilm(t *testing.T) {
 	ogFilm := &Film{
 		Slug: "sweet-sweetbacks-baadasssss-song",
@@ -174,6 +564,44 @@ func TestFilmsList(t *testing.T) {
 	require.Equal(t, 72, len(got))
 }
 
+func TestFilmsListPopularityRank(t *testing.T) {
+	got, err := sc.Film.List(context.Background(), &FilmListOpts{
+		SortBy:    "popular",
+		PageCount: 2,
+	})
+	require.NoError(t, err)
+	require.Greater(t, len(got), 72)
+	for i, film := range got {
+		require.Equal(t, i+1, film.PopularityRank, film.Slug)
+	}
+
+	got, err = sc.Film.List(context.Background(), &FilmListOpts{
+		SortBy:       "popular",
+		PageCount:    2,
+		ShufflePages: true,
+	})
+	require.NoError(t, err)
+	for _, film := range got {
+		require.Equal(t, 0, film.PopularityRank, film.Slug)
+	}
+}
+
+func TestFilmsRandom(t *testing.T) {
+	got, err := sc.Film.Random(context.Background(), &FilmListOpts{
+		SortBy: "popular",
+	})
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	require.NotEmpty(t, got.Slug)
+	// Enhanced from the film page
+	require.NotNil(t, got.ExternalIDs)
+	require.Equal(t, "tt0067810", got.ExternalIDs.IMDB)
+
+	got, err = sc.Film.Random(context.Background(), nil)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+}
+
 func TestSendRequestCached(t *testing.T) {
 	// First fetch should not be from the cache
 	sccMock.ClearExpect()
@@ -196,3 +624,614 @@ func TestSendRequestCached(t *testing.T) {
 	// require.Equal(t, true, resp.FromCache)
 	require.NoError(t, sccMock.ExpectationsWereMet())
 }
+
+func TestFilmSetBySlug(t *testing.T) {
+	films := FilmSet{
+		{Slug: "cure", Title: "Cure"},
+		{Slug: "", Title: "No Slug"},
+		{Slug: "nope", Title: "Nope"},
+		{Slug: "cure", Title: "Cure Again"},
+	}
+	got := films.BySlug()
+	require.Equal(t, 2, len(got))
+	require.Equal(t, "Cure Again", got["cure"].Title)
+	require.Equal(t, "Nope", got["nope"].Title)
+	_, ok := got[""]
+	require.False(t, ok)
+}
+
+func TestFilmSetByIMDB(t *testing.T) {
+	films := FilmSet{
+		{Slug: "cure", ExternalIDs: &ExternalFilmIDs{IMDB: "tt0123948"}},
+		{Slug: "no-ids"},
+		{Slug: "empty-ids", ExternalIDs: &ExternalFilmIDs{}},
+	}
+	got := films.ByIMDB()
+	require.Equal(t, 1, len(got))
+	require.Equal(t, "cure", got["tt0123948"].Slug)
+}
+
+// filmsWithJSONL unmarshals JSON Lines output one line at a time
+func filmsWithJSONL(t *testing.T, out string) FilmSet {
+	var films FilmSet
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\n"), "\n") {
+		var film Film
+		require.NoError(t, json.Unmarshal([]byte(line), &film))
+		films = append(films, &film)
+	}
+	return films
+}
+
+func TestFilmSetWriteJSONL(t *testing.T) {
+	films := FilmSet{
+		{Slug: "cure", Title: "Cure", Year: 1997, ExternalIDs: &ExternalFilmIDs{IMDB: "tt0123948"}},
+		nil,
+		{Slug: "pulse", Title: "Pulse", Year: 2001},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, films.WriteJSONL(&buf))
+	require.Equal(t, 2, strings.Count(buf.String(), "\n"))
+	require.Equal(t, FilmSet{films[0], films[2]}, filmsWithJSONL(t, buf.String()))
+}
+
+func TestWriteFilmsJSONL(t *testing.T) {
+	filmC := make(chan *Film)
+	done := make(chan error)
+	go sc.User.StreamWatchList(context.TODO(), "someguy", filmC, done)
+	var buf bytes.Buffer
+	require.NoError(t, WriteFilmsJSONL(&buf, filmC, done))
+	films := filmsWithJSONL(t, buf.String())
+	require.Equal(t, 84, len(films))
+	for _, film := range films {
+		require.NotEmpty(t, film.Slug)
+	}
+}
+
+func TestFilmSetRuntime(t *testing.T) {
+	films := FilmSet{
+		{Slug: "cure", Runtime: 111},
+		{Slug: "unknown"},
+		{Slug: "la-jetee", Runtime: 28},
+		nil,
+		{Slug: "pulse", Runtime: 119},
+	}
+	require.Equal(t, 258*time.Minute, films.TotalRuntime())
+	require.Equal(t, 86*time.Minute, films.AverageRuntime())
+
+	none := FilmSet{{Slug: "unknown"}}
+	require.Equal(t, time.Duration(0), none.TotalRuntime())
+	require.Equal(t, time.Duration(0), none.AverageRuntime())
+	require.Equal(t, time.Duration(0), (&FilmSet{}).AverageRuntime())
+}
+
+func TestFilmGetWithURL(t *testing.T) {
+	film, err := sc.Film.Get(context.TODO(), "https://letterboxd.com/film/sweet-sweetbacks-baadasssss-song/")
+	require.NoError(t, err)
+	require.Equal(t, "48640", film.ID)
+
+	_, err = sc.Film.Get(context.TODO(), "https://letterboxd.com/dave/list/official-top-250-narrative-feature-films/")
+	require.Error(t, err)
+}
+
+// sourceTrackingTransport records how many different batch sources have
+// requests in flight at the same time
+type sourceTrackingTransport struct {
+	mu         sync.Mutex
+	inFlight   map[string]int
+	maxSources int
+}
+
+func (s *sourceTrackingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	var source string
+	for _, prefix := range []string{"/singleguy/films/", "/singleguy/watchlist/", "/dave/list/"} {
+		if strings.HasPrefix(r.URL.Path, prefix) {
+			source = prefix
+		}
+	}
+	if source != "" {
+		s.mu.Lock()
+		s.inFlight[source]++
+		var active int
+		for _, v := range s.inFlight {
+			if v > 0 {
+				active++
+			}
+		}
+		s.maxSources = max(s.maxSources, active)
+		s.mu.Unlock()
+		// Give the other sources a chance to overlap
+		time.Sleep(100 * time.Millisecond)
+		defer func() {
+			s.mu.Lock()
+			s.inFlight[source]--
+			s.mu.Unlock()
+		}()
+	}
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestStreamBatchConcurrent(t *testing.T) {
+	tr := &sourceTrackingTransport{inFlight: map[string]int{}}
+	c := New(WithNoCache(), WithBaseURL(srv.URL))
+	c.client.Transport = tr
+
+	filmC := make(chan *Film)
+	errorC := make(chan error)
+	go c.Film.StreamBatch(context.TODO(), &FilmBatchOpts{
+		Watched:   []string{"singleguy"},
+		WatchList: []string{"singleguy"},
+	}, filmC, errorC)
+	films, err := SlurpFilms(filmC, errorC)
+	require.NoError(t, err)
+	require.Equal(t, 2, tr.maxSources)
+
+	watched, _, err := sc.Film.ExtractFilmsWithPath(context.TODO(), "/singleguy/films/page/1")
+	require.NoError(t, err)
+	watchlist, _, err := sc.Film.ExtractFilmsWithPath(context.TODO(), "/singleguy/watchlist/page/1")
+	require.NoError(t, err)
+	require.Equal(t, len(watched)+len(watchlist), len(films))
+}
+
+func TestStreamBatchSourceError(t *testing.T) {
+	filmC := make(chan *Film)
+	errorC := make(chan error)
+	go sc.Film.StreamBatch(context.TODO(), &FilmBatchOpts{
+		Watched:   []string{"brokenguy"},
+		WatchList: []string{"singleguy"},
+	}, filmC, errorC)
+	_, err := SlurpFilms(filmC, errorC)
+	require.EqualError(t, err, `watched "brokenguy": error, status code: 500`)
+
+	go sc.Film.StreamBatch(context.TODO(), &FilmBatchOpts{
+		List: []*ListID{{User: "nobody", Slug: "not-a-list"}},
+	}, filmC, errorC)
+	_, err = SlurpFilms(filmC, errorC)
+	require.ErrorIs(t, err, ErrNotFound)
+	require.Contains(t, err.Error(), `list "nobody/not-a-list": `)
+}
+
+func TestStreamBatchLimit(t *testing.T) {
+	filmC := make(chan *Film)
+	errorC := make(chan error)
+	go sc.Film.StreamBatch(context.TODO(), &FilmBatchOpts{
+		Watched: []string{"someguy"},
+		List: []*ListID{
+			{User: "dave", Slug: "official-top-250-narrative-feature-films"},
+		},
+		WatchList: []string{"someguy"},
+		Limit:     50,
+	}, filmC, errorC)
+	films, err := SlurpFilms(filmC, errorC)
+	require.NoError(t, err)
+	require.Equal(t, 50, len(films))
+
+	// The caller cancelling is still an error
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	go sc.Film.StreamBatch(ctx, &FilmBatchOpts{
+		Watched: []string{"someguy"},
+		Limit:   50,
+	}, filmC, errorC)
+	_, err = SlurpFilms(filmC, errorC)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestStreamBatchErr(t *testing.T) {
+	filmC := make(chan *Film)
+	errorC := make(chan error)
+	go sc.Film.StreamBatch(context.TODO(), &FilmBatchOpts{
+		Watched: []string{"neverexist"},
+	}, filmC, errorC)
+	_, err := SlurpFilms(filmC, errorC)
+	require.Error(t, err)
+}
+
+// countingTransport counts, and records, the requests made for paths starting
+// with prefix
+type countingTransport struct {
+	mu     sync.Mutex
+	prefix string
+	count  int
+	urls   []*url.URL
+}
+
+func (c *countingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if strings.HasPrefix(r.URL.Path, c.prefix) {
+		c.mu.Lock()
+		c.count++
+		c.urls = append(c.urls, r.URL)
+		c.mu.Unlock()
+	}
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestEnhanceFilmListDedupe(t *testing.T) {
+	tr := &countingTransport{prefix: "/film/"}
+	c := New(WithNoCache(), WithBaseURL(srv.URL))
+	c.client.Transport = tr
+
+	films := FilmSet{
+		{Slug: "sweet-sweetbacks-baadasssss-song", Rank: 1},
+		{Slug: "sweet-sweetbacks-baadasssss-song", Rank: 2},
+		{Slug: "sweet-sweetbacks-baadasssss-song", Rank: 3},
+	}
+	require.NoError(t, c.Film.EnhanceFilmList(context.TODO(), &films))
+	require.Equal(t, 1, tr.count)
+	for idx, film := range films {
+		require.Equal(t, idx+1, film.Rank)
+		require.Equal(t, 1971, film.Year)
+		require.Equal(t, "tt0067810", film.ExternalIDs.IMDB)
+	}
+}
+
+// cancellingTransport cancels a context once it has seen a number of film page
+// requests
+type cancellingTransport struct {
+	countingTransport
+	after  int
+	cancel context.CancelFunc
+}
+
+func (c *cancellingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	c.countingTransport.mu.Lock()
+	if strings.HasPrefix(r.URL.Path, c.prefix) && c.count+1 >= c.after {
+		c.cancel()
+	}
+	c.countingTransport.mu.Unlock()
+	return c.countingTransport.RoundTrip(r)
+}
+
+func TestExtractEnhancedFilmsWithPathCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	tr := &cancellingTransport{countingTransport: countingTransport{prefix: "/film/"}, after: 3, cancel: cancel}
+	c := New(WithNoCache(), WithBaseURL(srv.URL))
+	c.client.Transport = tr
+
+	films, pagination, err := c.Film.ExtractEnhancedFilmsWithPath(ctx, "/films/ajax/popular/size/small/page/1")
+	require.ErrorIs(t, err, context.Canceled)
+	require.NotNil(t, pagination)
+	// Partial results come back, without looking up every film
+	require.Equal(t, 72, len(films))
+	require.Less(t, tr.count, 72)
+	var unenhanced int
+	for _, film := range films {
+		if film.ExternalIDs == nil {
+			unenhanced++
+		}
+	}
+	require.Greater(t, unenhanced, 0)
+}
+
+func TestExtractReviewers(t *testing.T) {
+	f, err := os.Open("testdata/film/reviews/1.html")
+	require.NoError(t, err)
+	defer f.Close()
+	items, pagination, err := ExtractReviewers(f)
+	require.NoError(t, err)
+	require.Equal(t, []string{"karsten", "mia", "jay"}, items)
+	require.False(t, pagination.IsLast)
+}
+
+func TestFilmReviewers(t *testing.T) {
+	got, err := sc.Film.Reviewers(context.TODO(), "cure", 0)
+	require.NoError(t, err)
+	require.Equal(t, []string{"karsten", "mia", "jay", "lucy", "sam"}, got)
+
+	got, err = sc.Film.Reviewers(context.TODO(), "cure", 2)
+	require.NoError(t, err)
+	require.Equal(t, []string{"karsten", "mia"}, got)
+
+	got, err = sc.Film.Reviewers(context.TODO(), "cure", 4)
+	require.NoError(t, err)
+	require.Equal(t, []string{"karsten", "mia", "jay", "lucy"}, got)
+}
+
+func TestFilmographyNotEnhanced(t *testing.T) {
+	tr := &countingTransport{prefix: "/film/"}
+	c := New(WithNoCache(), WithBaseURL(srv.URL))
+	c.client.Transport = tr
+
+	enhance := false
+	films, err := c.Film.Filmography(context.TODO(), &FilmographyOpt{
+		Person:          "nicolas-cage",
+		Profession:      "actor",
+		IncludeEnhanced: &enhance,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 116, len(films))
+	require.Equal(t, "Spider-Man: Into the Spider-Verse", films[0].Title)
+	require.Equal(t, 0, tr.count)
+}
+
+func TestFilmographyPosterSize(t *testing.T) {
+	tr := &countingTransport{prefix: "/actor/nicolas-cage/size/large/"}
+	c := New(WithNoCache(), WithBaseURL(srv.URL))
+	c.client.Transport = tr
+
+	enhance := false
+	_, err := c.Film.Filmography(context.TODO(), &FilmographyOpt{
+		Person:          "nicolas-cage",
+		Profession:      "actor",
+		IncludeEnhanced: &enhance,
+		PosterSize:      "large",
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, tr.count)
+}
+
+func TestFilmographyEmpty(t *testing.T) {
+	films, err := sc.Film.Filmography(context.TODO(), &FilmographyOpt{
+		Person:     "nicolas-cage",
+		Profession: "writer",
+	})
+	require.ErrorIs(t, err, ErrNoFilmography)
+	require.NotNil(t, films)
+	require.Empty(t, films)
+}
+
+func TestFilmographyNotFound(t *testing.T) {
+	films, err := sc.Film.Filmography(context.TODO(), &FilmographyOpt{
+		Person:     "never-existed",
+		Profession: "director",
+	})
+	require.ErrorIs(t, err, ErrNotFound)
+	require.NotErrorIs(t, err, ErrNoFilmography)
+	require.Nil(t, films)
+}
+
+func TestFilmsListHighestRated(t *testing.T) {
+	got, err := sc.Film.List(context.Background(), &FilmListOpts{
+		SortBy: "by/rating",
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, len(got))
+	require.Equal(t, 4.62, got[0].AverageRating)
+	require.Equal(t, 4.58, got[1].AverageRating)
+	// Falls back to the tooltip
+	require.Equal(t, 4.55, got[2].AverageRating)
+}
+
+func TestPreviewsWithDocNoAverage(t *testing.T) {
+	f, err := os.Open("testdata/user/films.html")
+	require.NoError(t, err)
+	defer f.Close()
+	films := previewsWithDoc(mustNewDocumentFromReader(f))
+	require.NotEmpty(t, films)
+	require.Equal(t, float64(0), films[0].AverageRating)
+}
+
+func TestPreviewsWithDocUserRating(t *testing.T) {
+	f, err := os.Open("testdata/user/watched-paginated/2.html")
+	require.NoError(t, err)
+	defer f.Close()
+	films := previewsWithDoc(mustNewDocumentFromReader(f))
+	require.NotEmpty(t, films)
+	require.Equal(t, "Zombieland: Double Tap", films[0].Title)
+	require.Equal(t, intPtr(8), films[0].UserRating)
+}
+
+func TestSlurpFilmsN(t *testing.T) {
+	tr := &countingTransport{prefix: "/someguy/watchlist/"}
+	c := New(WithNoCache(), WithBaseURL(srv.URL))
+	c.client.Transport = tr
+
+	ctx, cancel := context.WithCancel(context.Background())
+	filmC := make(chan *Film)
+	errorC := make(chan error)
+	go c.User.StreamWatchList(ctx, "someguy", filmC, errorC)
+	films, err := SlurpFilmsN(cancel, filmC, errorC, 10)
+	require.NoError(t, err)
+	require.Equal(t, 10, len(films))
+	require.Error(t, ctx.Err())
+	// Only the first of the 3 pages should have been needed
+	require.Equal(t, 1, tr.count)
+}
+
+func TestSlurpFilmsNFewerThanN(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	filmC := make(chan *Film)
+	errorC := make(chan error)
+	go sc.User.StreamWatchList(ctx, "singleguy", filmC, errorC)
+	films, err := SlurpFilmsN(cancel, filmC, errorC, 1000)
+	require.NoError(t, err)
+	require.Greater(t, len(films), 0)
+	require.Less(t, len(films), 1000)
+}
+
+func TestExtractFilmLists(t *testing.T) {
+	f, err := os.Open("testdata/film/lists/1.html")
+	require.NoError(t, err)
+	defer f.Close()
+	items, pagination, err := ExtractFilmLists(f)
+	require.NoError(t, err)
+	require.Equal(t, 2, pagination.TotalPages)
+	lists := items.([]*List)
+	require.Equal(t, 3, len(lists))
+	require.Equal(t, &List{
+		Title:      "J-Horror Essentials",
+		Owner:      "karsten",
+		Slug:       "j-horror-essentials",
+		Visibility: ListVisibilityPublic,
+	}, lists[0])
+}
+
+func TestFilmListedBy(t *testing.T) {
+	got, err := sc.Film.ListedBy(context.TODO(), "cure", 0)
+	require.NoError(t, err)
+	require.Equal(t, 5, len(got))
+	require.Equal(t, "sam", got[4].Owner)
+	require.Equal(t, "detectives", got[4].Slug)
+
+	got, err = sc.Film.ListedBy(context.TODO(), "cure", 2)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(got))
+	require.Equal(t, "kurosawa-ranked", got[1].Slug)
+
+	got, err = sc.Film.ListedBy(context.TODO(), "cure", 4)
+	require.NoError(t, err)
+	require.Equal(t, 4, len(got))
+	require.Equal(t, "lucy", got[3].Owner)
+}
+
+func TestExtractMembers(t *testing.T) {
+	f, err := os.Open("testdata/film/members/rated-5-1.html")
+	require.NoError(t, err)
+	defer f.Close()
+	items, pagination, err := ExtractMembers(f)
+	require.NoError(t, err)
+	require.Equal(t, []string{"karsten", "mia"}, items.([]string))
+	require.Equal(t, 2, pagination.TotalPages)
+}
+
+func TestFilmWatchedBy(t *testing.T) {
+	got, err := sc.Film.WatchedBy(context.TODO(), "cure", 4.5, 0)
+	require.NoError(t, err)
+	require.Equal(t, []string{"karsten", "mia", "jay", "lucy"}, got)
+
+	got, err = sc.Film.WatchedBy(context.TODO(), "cure", 5, 0)
+	require.NoError(t, err)
+	require.Equal(t, []string{"karsten", "mia", "jay"}, got)
+
+	got, err = sc.Film.WatchedBy(context.TODO(), "cure", 3, 2)
+	require.NoError(t, err)
+	require.Equal(t, []string{"karsten", "mia"}, got)
+
+	for _, bad := range []float64{0, 4.2, 5.5, -1} {
+		_, err = sc.Film.WatchedBy(context.TODO(), "cure", bad, 0)
+		require.Error(t, err, bad)
+	}
+}
+
+func TestFilmPage(t *testing.T) {
+	for desc, path := range map[string]string{
+		"relative": "/someguy/films/page/1/",
+		"no-slash": "someguy/films/page/1/",
+		"absolute": srv.URL + "/someguy/films/page/1/",
+	} {
+		films, pagination, err := sc.Film.Page(context.TODO(), path)
+		require.NoError(t, err, desc)
+		require.Equal(t, 72, len(films), desc)
+		require.Equal(t, 1, pagination.CurrentPage, desc)
+		require.Equal(t, 5, pagination.TotalPages, desc)
+		require.False(t, pagination.IsLast, desc)
+		// Not enhanced
+		require.Nil(t, films[0].ExternalIDs, desc)
+	}
+
+	// The older name still works the same
+	films, pagination, err := sc.Film.ExtractFilmsWithPath(context.TODO(), "/someguy/films/page/1/")
+	require.NoError(t, err)
+	require.Equal(t, 72, len(films))
+	require.Equal(t, 5, pagination.TotalPages)
+}
+
+func TestFilmGetAgeGated(t *testing.T) {
+	film, err := sc.Film.Get(context.TODO(), "age-gated")
+	require.ErrorIs(t, err, ErrAgeGated)
+	require.Nil(t, film)
+
+	// A session that has confirmed its age gets the real page
+	c := New(WithNoCache(), WithBaseURL(srv.URL), WithDefaultHeaders(http.Header{
+		"Cookie": []string{"letterboxd.adult=true"},
+	}))
+	film, err = c.Film.Get(context.TODO(), "age-gated")
+	require.NoError(t, err)
+	require.Equal(t, "Sweet Sweetback's Baadasssss Song", film.Title)
+}
+
+func TestExtractFilmFromFilmPageAgeGate(t *testing.T) {
+	f, err := os.Open("testdata/film/age-gate.html")
+	require.NoError(t, err)
+	defer f.Close()
+	_, _, err = extractFilmFromFilmPage(f)
+	require.ErrorIs(t, err, ErrAgeGated)
+}
+
+func TestGetWatchedIMDBIDsError(t *testing.T) {
+	errC := make(chan error)
+	go func() {
+		_, err := sc.Film.GetWatchedIMDBIDs(context.Background(), "brokenguy")
+		errC <- err
+	}()
+	select {
+	case err := <-errC:
+		require.EqualError(t, err, "error, status code: 500")
+	case <-time.After(5 * time.Second):
+		t.Fatal("GetWatchedIMDBIDs did not return the stream error")
+	}
+}
+
+func TestGetWatchedIMDBIDsCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := sc.Film.GetWatchedIMDBIDs(ctx, "someguy")
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestFilmSimilar(t *testing.T) {
+	tests := map[string][]string{
+		"similar": {"pulse", "charisma", "ringu"},
+		"genre":   {"memories-of-murder", "seven"},
+		"theme":   {"charisma", "serpents-path", "pulse", "creepy"},
+	}
+	for _, kind := range SimilarKinds {
+		films, err := sc.Film.Similar(context.TODO(), "cure", kind)
+		require.NoError(t, err, kind)
+		var slugs []string
+		for _, film := range films {
+			slugs = append(slugs, film.Slug)
+		}
+		require.Equal(t, tests[kind], slugs, kind)
+	}
+
+	films, err := sc.Film.Similar(context.TODO(), "https://letterboxd.com/film/cure/", "genre")
+	require.NoError(t, err)
+	require.Equal(t, "Memories of Murder", films[0].Title)
+	require.Equal(t, "51568", films[0].ID)
+
+	// The overall suggestions are the default
+	films, err = sc.Film.Similar(context.TODO(), "cure", "")
+	require.NoError(t, err)
+	require.Equal(t, "pulse", films[0].Slug)
+
+	_, err = sc.Film.Similar(context.TODO(), "cure", "mood")
+	require.EqualError(t, err, "kind must be one of [similar genre theme]")
+}
+
+func TestFilmRatings(t *testing.T) {
+	breakdown, err := sc.Film.Ratings(context.TODO(), "cure")
+	require.NoError(t, err)
+	require.Equal(t, 4.07, breakdown.Average)
+	require.Equal(t, 98512, breakdown.Count)
+	require.Equal(t, 10, len(breakdown.Distribution))
+	require.Equal(t, 212, breakdown.Distribution[1])
+	require.Equal(t, 13822, breakdown.Distribution[7])
+	require.Equal(t, 21772, breakdown.Distribution[MaxRating])
+
+	_, err = sc.Film.Ratings(context.TODO(), "/someguy/list/foo/")
+	require.Error(t, err)
+}
+
+func TestFilmStatistics(t *testing.T) {
+	stats, err := sc.Film.Statistics(context.TODO(), "cure")
+	require.NoError(t, err)
+	require.Equal(t, &FilmStatistics{Watches: 312456, Likes: 98765, Lists: 45678}, stats)
+
+	// No stats on the page
+	_, _, err = ExtractFilmStatistics(strings.NewReader(`<html><body></body></html>`))
+	require.EqualError(t, err, "no film stats found")
+}
+
+func TestFilmReleases(t *testing.T) {
+	releases, err := sc.Film.Releases(context.TODO(), "pulse")
+	require.NoError(t, err)
+	require.Equal(t, 5, len(releases))
+	require.Equal(t, Release{Type: "Premiere", Country: "France", Date: time.Date(2001, 5, 13, 0, 0, 0, 0, time.UTC)}, releases[0])
+	require.Equal(t, "Physical", releases[4].Type)
+
+	// No releases listed
+	releases, err = sc.Film.Releases(context.TODO(), "https://letterboxd.com/film/sweet-sweetbacks-baadasssss-song/")
+	require.NoError(t, err)
+	require.Empty(t, releases)
+}
@@ -2,12 +2,22 @@ package letterboxd
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
 )
 
 // ListService is the interface defining which methods we can use for List items
 type ListService interface {
 	GetOfficialMap(context.Context) map[string]string
 	GetOfficial(context.Context) []*ListID
+	ListFilms(context.Context, *ListFilmsOpt) (FilmSet, error)
+	OfficialBySlug(string) (*ListID, bool)
+	FilmsFromURL(context.Context, string) (*List, FilmSet, error)
 }
 
 // ListServiceOp is the Operator for the ListService
@@ -21,12 +31,183 @@ type ListID struct {
 	Slug string
 }
 
+// Visibility of a list
+const (
+	ListVisibilityPublic   = "public"
+	ListVisibilityUnlisted = "unlisted"
+	ListVisibilityPrivate  = "private"
+)
+
+// List is the metadata about a list, without the films in it
+type List struct {
+	Title      string     `json:"title"`
+	Owner      string     `json:"owner"` // Username of the user who owns the list
+	Slug       string     `json:"slug"`
+	Ranked     bool       `json:"ranked"`
+	Visibility string     `json:"visibility"` // One of the ListVisibility constants
+	Created    *time.Time `json:"created,omitempty"`
+	Updated    *time.Time `json:"updated,omitempty"` // Only set if the list was changed after it was published
+}
+
 // ListFilmsOpt is the options for the ListFilms method
 type ListFilmsOpt struct {
 	User      string // Username of the user for the list. Example: 'dave'
 	Slug      string // Slug of the list: Example: 'official-top-250-narrative-feature-films'
 	FirstPage int    // First page to fetch. Defaults to 1
 	LastPage  int    // Last page to fetch. Defaults to FirstPage. Use -1 to fetch all pages
+	// IncludeNotes uses the detail view of the list, so that the notes are
+	// returned along with the rank in a single pass
+	IncludeNotes bool
+}
+
+// ListFilms returns the films in a list, along with their rank in the list
+func (l *ListServiceOp) ListFilms(ctx context.Context, opt *ListFilmsOpt) (FilmSet, error) {
+	firstPage, lastPage, err := normalizeStartStop(opt.FirstPage, opt.LastPage)
+	if err != nil {
+		return nil, err
+	}
+	var films FilmSet
+	for page := firstPage; lastPage < 0 || page <= lastPage; page++ {
+		path := fmt.Sprintf("%s/%s/list/%s/", l.client.baseURL, opt.User, opt.Slug)
+		if opt.IncludeNotes {
+			path += "detail/"
+		}
+		req := mustNewGetRequest(fmt.Sprintf("%spage/%v/", path, page))
+		items, resp, err := l.client.sendRequest(req, ExtractListFilms)
+		if err != nil {
+			return nil, err
+		}
+		if resp.Response != nil {
+			dclose(resp.Body)
+		}
+		partialFilms := items.Data.(FilmSet)
+		if err := l.client.Film.EnhanceFilmList(ctx, &partialFilms); err != nil {
+			return nil, err
+		}
+		films = append(films, partialFilms...)
+		if items.Pagination.IsLast {
+			break
+		}
+	}
+	return films, nil
+}
+
+// FilmsFromURL returns the metadata and every film of the list at a full
+// Letterboxd list URL. Films are enhanced, and carry their rank in the list
+func (l *ListServiceOp) FilmsFromURL(ctx context.Context, lurl string) (*List, FilmSet, error) {
+	id, err := listIDWithURL(lurl)
+	if err != nil {
+		return nil, nil, err
+	}
+	list, _, _, err := l.client.listFirstPage(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	films, err := l.ListFilms(ctx, &ListFilmsOpt{
+		User:      id.User,
+		Slug:      id.Slug,
+		FirstPage: 1,
+		LastPage:  -1,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return list, films, nil
+}
+
+// ExtractListFilms returns the films of a list from an io.Reader, including
+// their rank, and notes when using the detail view
+func ExtractListFilms(r io.Reader) (interface{}, *Pagination, error) {
+	doc, err := newDocumentFromReader(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	return listFilmsWithDoc(doc), paginationOrSinglePage(doc), nil
+}
+
+// ExtractList returns the List metadata, films and pagination from a list page,
+// such as one saved for offline use
+func ExtractList(r io.Reader) (*List, FilmSet, *Pagination, error) {
+	doc, err := newDocumentFromReader(r)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return listWithDoc(doc), listFilmsWithDoc(doc), paginationOrSinglePage(doc), nil
+}
+
+func listFilmsWithDoc(doc *goquery.Document) FilmSet {
+	var films FilmSet
+	doc.Find("li.numbered-list-item").Each(func(i int, s *goquery.Selection) {
+		poster := s.Find("div.film-poster").First()
+		f := &Film{
+			ID:     poster.AttrOr("data-film-id", ""),
+			Slug:   normalizeSlug(poster.AttrOr("data-film-slug", "")),
+			Target: poster.AttrOr("data-target-link", ""),
+			Title:  poster.Find("img.image").AttrOr("alt", ""),
+		}
+		if rank, err := strconv.Atoi(strings.TrimSpace(s.Find("p.list-number").Text())); err == nil {
+			f.Rank = rank
+		}
+		var paragraphs []string
+		s.Find("div.film-detail-content").Find("div.body-text").Find("p").Each(func(i int, p *goquery.Selection) {
+			paragraphs = append(paragraphs, strings.TrimSpace(p.Text()))
+		})
+		f.Note = strings.Join(paragraphs, "\n")
+		films = append(films, f)
+	})
+	// Unranked lists don't number their entries
+	if len(films) == 0 {
+		films = previewsWithDoc(doc)
+	}
+	return films
+}
+
+// ExtractListMetadata returns the List metadata from an io.Reader
+func ExtractListMetadata(r io.Reader) (interface{}, *Pagination, error) {
+	doc, err := newDocumentFromReader(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	return listWithDoc(doc), nil, nil
+}
+
+func listWithDoc(doc *goquery.Document) *List {
+	l := &List{
+		Owner:      doc.Find("body").AttrOr("data-owner", ""),
+		Ranked:     doc.Find("li.numbered-list-item").Length() > 0,
+		Visibility: ListVisibilityPublic,
+	}
+	marker := doc.Find(".list-visibility").First()
+	switch {
+	case marker.HasClass("-unlisted"):
+		l.Visibility = ListVisibilityUnlisted
+	case marker.HasClass("-private"):
+		l.Visibility = ListVisibilityPrivate
+	}
+	l.Created = listDateWithSelection(doc.Find("p.list-date span.published"))
+	l.Updated = listDateWithSelection(doc.Find("p.list-date span.updated"))
+	doc.Find("meta").Each(func(i int, s *goquery.Selection) {
+		switch s.AttrOr("property", "") {
+		case "og:title":
+			l.Title = s.AttrOr("content", "")
+		case "og:url":
+			parts := strings.Split(strings.Trim(s.AttrOr("content", ""), "/"), "/")
+			if len(parts) >= 2 && parts[len(parts)-2] == "list" {
+				l.Slug = parts[len(parts)-1]
+			}
+		}
+	})
+	return l
+}
+
+// listDateWithSelection returns the time from a list date span, or nil if it
+// is missing
+func listDateWithSelection(s *goquery.Selection) *time.Time {
+	t, err := time.Parse(time.RFC3339, s.Find("time").First().AttrOr("datetime", ""))
+	if err != nil {
+		return nil
+	}
+	return &t
 }
 
 // GetOfficialMap returns the official letterboxd lists using the slug as the key
@@ -38,6 +219,17 @@ func (l *ListServiceOp) GetOfficialMap(ctx context.Context) map[string]string {
 	return ret
 }
 
+// OfficialBySlug returns the official list with the given slug, and false if
+// the slug is not one of the official lists
+func (l *ListServiceOp) OfficialBySlug(slug string) (*ListID, bool) {
+	for _, i := range l.GetOfficial(context.Background()) {
+		if i.Slug == slug {
+			return i, true
+		}
+	}
+	return nil, false
+}
+
 // GetOfficial returns the official lists as a slice
 func (l *ListServiceOp) GetOfficial(ctx context.Context) []*ListID {
 	return []*ListID{
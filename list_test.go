@@ -2,7 +2,10 @@ package letterboxd
 
 import (
 	"context"
+	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -12,3 +15,131 @@ func TestGetOfficialListMap(t *testing.T) {
 	require.NotNil(t, got)
 	require.Greater(t, len(got), 0)
 }
+
+func TestOfficialBySlug(t *testing.T) {
+	got, ok := sc.List.OfficialBySlug("academy-award-winners-for-best-picture")
+	require.True(t, ok)
+	require.Equal(t, &ListID{User: "jake_ziegler", Slug: "academy-award-winners-for-best-picture"}, got)
+
+	got, ok = sc.List.OfficialBySlug("never-heard-of-it")
+	require.False(t, ok)
+	require.Nil(t, got)
+}
+
+func TestExtractListFilms(t *testing.T) {
+	f, err := os.Open("testdata/list/top250.html")
+	require.NoError(t, err)
+	defer f.Close()
+	items, pagination, err := ExtractListFilms(f)
+	require.NoError(t, err)
+	require.NotNil(t, pagination)
+	films := items.(FilmSet)
+	require.Equal(t, 100, len(films))
+	require.Equal(t, 1, films[0].Rank)
+	require.Equal(t, "everything-everywhere-all-at-once", films[0].Slug)
+	require.Equal(t, "", films[0].Note)
+}
+
+func TestExtractList(t *testing.T) {
+	f, err := os.Open("testdata/list/lists-page-1.html")
+	require.NoError(t, err)
+	defer f.Close()
+	list, films, pagination, err := ExtractList(f)
+	require.NoError(t, err)
+	created := time.Date(2013, 11, 8, 10, 38, 22, 0, time.UTC)
+	updated := time.Date(2022, 5, 9, 14, 31, 13, 0, time.UTC)
+	require.Equal(t, &List{
+		Title:      "Official Top 250 Narrative Feature Films",
+		Owner:      "dave",
+		Slug:       "official-top-250-narrative-feature-films",
+		Ranked:     true,
+		Visibility: ListVisibilityPublic,
+		Created:    &created,
+		Updated:    &updated,
+	}, list)
+	require.Equal(t, 100, len(films))
+	require.Equal(t, 1, films[0].Rank)
+	require.Equal(t, 1, pagination.CurrentPage)
+	require.Equal(t, 3, pagination.TotalPages)
+}
+
+func TestExtractListUnranked(t *testing.T) {
+	f, err := os.Open("testdata/list/lists-single-page.html")
+	require.NoError(t, err)
+	defer f.Close()
+	list, films, pagination, err := ExtractList(f)
+	require.NoError(t, err)
+	require.Equal(t, "2022 - Movie Church", list.Title)
+	require.Equal(t, "mondodrew", list.Owner)
+	require.False(t, list.Ranked)
+	require.Equal(t, ListVisibilityPublic, list.Visibility)
+	require.Equal(t, 13, len(films))
+	require.Equal(t, 0, films[0].Rank)
+	require.True(t, pagination.IsLast)
+}
+
+func TestExtractListUnlisted(t *testing.T) {
+	f, err := os.Open("testdata/list/unlisted.html")
+	require.NoError(t, err)
+	defer f.Close()
+	list, _, _, err := ExtractList(f)
+	require.NoError(t, err)
+	require.Equal(t, ListVisibilityUnlisted, list.Visibility)
+	require.Equal(t, "2022 - Movie Church", list.Title)
+}
+
+func TestListWithDocDates(t *testing.T) {
+	// Never updated
+	doc := mustNewDocumentFromReader(strings.NewReader(`<p class="list-date"> <span class="published">Published <time datetime="2022-02-14T22:13:48Z">2022-02-14T22:13:48Z</time></span> </p>`))
+	l := listWithDoc(doc)
+	require.Equal(t, "2022-02-14T22:13:48Z", l.Created.Format(time.RFC3339))
+	require.Nil(t, l.Updated)
+
+	// No dates at all
+	f, err := os.Open("testdata/list/ranked-notes.html")
+	require.NoError(t, err)
+	defer f.Close()
+	l = listWithDoc(mustNewDocumentFromReader(f))
+	require.Nil(t, l.Created)
+	require.Nil(t, l.Updated)
+}
+
+func TestListWithDocPrivate(t *testing.T) {
+	doc := mustNewDocumentFromReader(strings.NewReader(`<h1 class="title-1">Secrets <span class="list-visibility -private">Private</span></h1>`))
+	require.Equal(t, ListVisibilityPrivate, listWithDoc(doc).Visibility)
+}
+
+func TestListFilmsWithNotes(t *testing.T) {
+	films, err := sc.List.ListFilms(context.TODO(), &ListFilmsOpt{
+		User:         "someguy",
+		Slug:         "ranked-notes",
+		IncludeNotes: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, len(films))
+	require.Equal(t, 1, films[0].Rank)
+	require.Equal(t, "Parasite", films[0].Title)
+	require.Equal(t, "Still the best thing I have seen in a theater.\nWatch it twice.", films[0].Note)
+	require.Equal(t, 2, films[1].Rank)
+	require.Equal(t, "", films[1].Note)
+}
+
+func TestListFilmsFromURL(t *testing.T) {
+	list, films, err := sc.List.FilmsFromURL(context.TODO(), "https://letterboxd.com/dave/list/official-top-250-narrative-feature-films/")
+	require.NoError(t, err)
+	require.Equal(t, "Official Top 250 Narrative Feature Films", list.Title)
+	require.Equal(t, "dave", list.Owner)
+	require.Equal(t, "official-top-250-narrative-feature-films", list.Slug)
+	require.True(t, list.Ranked)
+	require.Equal(t, 250, len(films))
+	for i, film := range films {
+		require.Equal(t, i+1, film.Rank)
+	}
+	// Enhanced from the film page
+	require.NotNil(t, films[0].ExternalIDs)
+
+	_, _, err = sc.List.FilmsFromURL(context.TODO(), "https://letterboxd.com/singleguy/films/")
+	require.EqualError(t, err, "not a list URL")
+	_, _, err = sc.List.FilmsFromURL(context.TODO(), "https://example.com/dave/list/official-top-250-narrative-feature-films/")
+	require.Error(t, err)
+}
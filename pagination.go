@@ -46,6 +46,27 @@ func (p *Pagination) SetTotalItems(i int) {
 	}
 }
 
+// setTotalItemsWithPages works out TotalItems for a stream from the item
+// counts of the first and last pages. A TotalItems parsed from the page is
+// kept, and a parsed ItemsPerPage is preferred over counting the first page.
+// With neither, an empty first page leaves TotalItems unknown, as 0
+func (p *Pagination) setTotalItemsWithPages(firstPageItems, lastPageItems int) {
+	if p.TotalItems > 0 {
+		return
+	}
+	if p.TotalPages <= 1 {
+		p.TotalItems = firstPageItems
+		return
+	}
+	if p.ItemsPerPage == 0 {
+		if firstPageItems == 0 {
+			return
+		}
+		p.ItemsPerPage = firstPageItems
+	}
+	p.TotalItems = p.ItemsPerPage*(p.TotalPages-1) + lastPageItems
+}
+
 // Given a URL, return the page number it contains. This is usually the last dir in the path section
 func pageWithURL(u string) (int, error) {
 	url := mustParseURL(u)
@@ -184,16 +205,44 @@ func ExtractPaginationWithDoc(doc *goquery.Document) (*Pagination, error) {
 	return p, nil
 }
 
+// singlePagePagination is the Pagination for a page that has no pagination
+// markup, meaning it's the one and only page
+func singlePagePagination() *Pagination {
+	return &Pagination{
+		CurrentPage: 1,
+		NextPage:    1,
+		TotalPages:  1,
+		IsLast:      true,
+	}
+}
+
+// paginationOrSinglePage returns the Pagination from a goquery Doc, falling
+// back to a single page if none could be found
+func paginationOrSinglePage(doc *goquery.Document) *Pagination {
+	p, err := ExtractPaginationWithDoc(doc)
+	if err != nil {
+		return singlePagePagination()
+	}
+	return p
+}
+
 // ExtractPagination pulls the pagination from an io.Reader
 func ExtractPagination(r io.Reader) (*Pagination, error) {
-	return ExtractPaginationWithDoc(mustNewDocumentFromReader(r))
+	doc, err := newDocumentFromReader(r)
+	if err != nil {
+		return nil, err
+	}
+	return ExtractPaginationWithDoc(doc)
 }
 
 // hasNext returns true if a page has more pages to show.  This is needed for
 // pagination that only shows if there is another page available, but not how
 // many total pages there are
-func hasNext(r io.Reader) bool {
-	doc := mustNewDocumentFromReader(r)
+func hasNext(r io.Reader) (bool, error) {
+	doc, err := newDocumentFromReader(r)
+	if err != nil {
+		return false, err
+	}
 
 	var ret bool
 	doc.Find("div.pagination").Find("a.next").EachWithBreak(func(i int, s *goquery.Selection) bool {
@@ -202,5 +251,5 @@ func hasNext(r io.Reader) bool {
 		}
 		return false
 	})
-	return ret
+	return ret, nil
 }
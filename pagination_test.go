@@ -115,7 +115,8 @@ func TestExtractHasNext(t *testing.T) {
 	b, err := os.Open("testdata/user/following/1.html")
 	require.NoError(t, err)
 	defer b.Close()
-	got := hasNext(b)
+	got, err := hasNext(b)
+	require.NoError(t, err)
 	require.True(t, got)
 }
 
@@ -123,21 +124,24 @@ func TestExtractNotHasNext(t *testing.T) {
 	b, err := os.Open("testdata/user/following/2.html")
 	require.NoError(t, err)
 	defer b.Close()
-	got := hasNext(b)
+	got, err := hasNext(b)
+	require.NoError(t, err)
 	require.False(t, got)
 }
 
 func TestExtractHasNextBytes(t *testing.T) {
 	b, err := os.ReadFile("testdata/user/following/1.html")
 	require.NoError(t, err)
-	got := hasNext(bytes.NewReader(b))
+	got, err := hasNext(bytes.NewReader(b))
+	require.NoError(t, err)
 	require.True(t, got)
 }
 
 func TestExtractNotHasNextBytes(t *testing.T) {
 	b, err := os.ReadFile("testdata/user/following/2.html")
 	require.NoError(t, err)
-	got := hasNext(bytes.NewReader(b))
+	got, err := hasNext(bytes.NewReader(b))
+	require.NoError(t, err)
 	require.False(t, got)
 }
 
@@ -235,6 +239,29 @@ func TestParseDivPaginationPrevious(t *testing.T) {
 	}
 }
 
+func TestSetTotalItemsWithPages(t *testing.T) {
+	tests := map[string]struct {
+		p     Pagination
+		first int
+		last  int
+		want  int
+	}{
+		"single-page":         {p: Pagination{TotalPages: 1}, first: 12, want: 12},
+		"counted":             {p: Pagination{TotalPages: 3}, first: 72, last: 10, want: 154},
+		"parsed-per-page":     {p: Pagination{TotalPages: 3, ItemsPerPage: 72}, first: 70, last: 10, want: 154},
+		"parsed-total":        {p: Pagination{TotalPages: 3, TotalItems: 150}, first: 72, last: 10, want: 150},
+		"zero-first-page":     {p: Pagination{TotalPages: 3}, last: 10, want: 0},
+		"zero-single-page":    {p: Pagination{TotalPages: 1}, want: 0},
+		"zero-with-per-page":  {p: Pagination{TotalPages: 2, ItemsPerPage: 72}, last: 5, want: 77},
+		"no-pages-parsed-yet": {p: Pagination{}, first: 3, want: 3},
+	}
+	for desc, tt := range tests {
+		p := tt.p
+		p.setTotalItemsWithPages(tt.first, tt.last)
+		require.Equal(t, tt.want, p.TotalItems, desc)
+	}
+}
+
 // selectWithString takes a string, and converts it in to a goquery.Selection. Really just useful for helping test
 func selectWithString(s string) *goquery.Selection {
 	doc := mustNewDocumentFromReader(strings.NewReader(s))
@@ -0,0 +1,134 @@
+package letterboxd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// PersonService is the interface for looking up the people credited on films
+type PersonService interface {
+	Get(ctx context.Context, slug string) (*Person, error)
+}
+
+// PersonServiceOp is the operator for the PersonService
+type PersonServiceOp struct {
+	client *Client
+}
+
+// Person is someone credited on films, along with all of their credits
+type Person struct {
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+	// KnownForDepartment is the profession with the most credits, like 'actor'
+	KnownForDepartment string `json:"known_for_department"`
+	// CreditsByProfession are the films for each profession the person is
+	// credited under. Films are not enhanced, use EnhanceFilmList for more
+	// details
+	CreditsByProfession map[string]FilmSet `json:"credits_by_profession"`
+}
+
+// personPage is a single profession page for a person
+type personPage struct {
+	name        string
+	professions []string // Other professions linked from the page
+	films       FilmSet
+}
+
+// extractPersonPage returns a personPage from an io.Reader
+func extractPersonPage(r io.Reader) (interface{}, *Pagination, error) {
+	doc, err := newDocumentFromReader(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	title := doc.Find("div.contextual-title h1").First().Clone()
+	title.Find(".context").Remove()
+	page := &personPage{
+		name:  strings.TrimSpace(title.Text()),
+		films: previewsWithDoc(doc),
+	}
+	doc.Find("#content-nav section.smenu-wrapper-left a.item").Each(func(i int, s *goquery.Selection) {
+		parts := strings.Split(strings.Trim(s.AttrOr("href", ""), "/"), "/")
+		if len(parts) == 2 {
+			page.professions = append(page.professions, parts[0])
+		}
+	})
+	return page, nil, nil
+}
+
+func (p *PersonServiceOp) personPageWithProfession(ctx context.Context, profession, slug string) (*personPage, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/%s/%s/", p.client.baseURL, profession, slug), nil)
+	if err != nil {
+		return nil, err
+	}
+	pData, resp, err := p.client.sendRequest(req, extractPersonPage)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Response != nil {
+		dclose(resp.Body)
+	}
+	page, ok := pData.Data.(*personPage)
+	if !ok {
+		return nil, errors.New("unexpected data type for person page")
+	}
+	return page, nil
+}
+
+// Get returns a Person, along with their credits for every profession they are
+// listed under
+func (p *PersonServiceOp) Get(ctx context.Context, slug string) (*Person, error) {
+	var first *personPage
+	var firstProfession string
+	for _, profession := range Professions {
+		page, err := p.personPageWithProfession(ctx, profession, slug)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		first, firstProfession = page, profession
+		break
+	}
+	if first == nil {
+		return nil, ErrNotFound
+	}
+
+	person := &Person{
+		Slug: slug,
+		Name: first.name,
+		CreditsByProfession: map[string]FilmSet{
+			firstProfession: first.films,
+		},
+	}
+	for _, profession := range first.professions {
+		if _, ok := person.CreditsByProfession[profession]; ok {
+			continue
+		}
+		page, err := p.personPageWithProfession(ctx, profession, slug)
+		if err != nil {
+			return nil, err
+		}
+		person.CreditsByProfession[profession] = page.films
+	}
+	person.KnownForDepartment = knownForWithCredits(firstProfession, first.professions, person.CreditsByProfession)
+	return person, nil
+}
+
+// knownForWithCredits returns the profession with the most credits. Ties go to
+// the profession listed first
+func knownForWithCredits(first string, others []string, credits map[string]FilmSet) string {
+	ret := first
+	for _, profession := range others {
+		if len(credits[profession]) > len(credits[ret]) {
+			ret = profession
+		}
+	}
+	return ret
+}
@@ -0,0 +1,50 @@
+package letterboxd
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPersonGet(t *testing.T) {
+	got, err := sc.Person.Get(context.TODO(), "nicolas-cage")
+	require.NoError(t, err)
+	require.Equal(t, "nicolas-cage", got.Slug)
+	require.Equal(t, "Nicolas Cage", got.Name)
+	require.Equal(t, "actor", got.KnownForDepartment)
+	require.Equal(t, 3, len(got.CreditsByProfession))
+	require.Greater(t, len(got.CreditsByProfession["actor"]), 3)
+	require.Equal(t, 3, len(got.CreditsByProfession["producer"]))
+	require.Equal(t, 1, len(got.CreditsByProfession["director"]))
+	require.Equal(t, "sonny", got.CreditsByProfession["director"][0].Slug)
+}
+
+func TestPersonGetNotFound(t *testing.T) {
+	got, err := sc.Person.Get(context.TODO(), "never-existed")
+	require.ErrorIs(t, err, ErrNotFound)
+	require.Nil(t, got)
+}
+
+func TestExtractPersonPage(t *testing.T) {
+	f, err := os.Open("testdata/filmography/producer/nicolas-cage.html")
+	require.NoError(t, err)
+	defer f.Close()
+	item, _, err := extractPersonPage(f)
+	require.NoError(t, err)
+	page := item.(*personPage)
+	require.Equal(t, "Nicolas Cage", page.name)
+	require.Equal(t, []string{"actor", "director"}, page.professions)
+	require.Equal(t, 3, len(page.films))
+}
+
+func TestKnownForWithCredits(t *testing.T) {
+	credits := map[string]FilmSet{
+		"actor":    {{Slug: "a"}},
+		"director": {{Slug: "b"}, {Slug: "c"}},
+		"writer":   {{Slug: "d"}, {Slug: "e"}},
+	}
+	require.Equal(t, "director", knownForWithCredits("actor", []string{"director", "writer"}, credits))
+	require.Equal(t, "actor", knownForWithCredits("actor", nil, credits))
+}
@@ -0,0 +1,124 @@
+package letterboxd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// ErrUnrated is returned when parsing a rating from a page that shows no rating
+var ErrUnrated = errors.New("no rating given")
+
+// Rating is a Letterboxd rating on the 0-10 half star scale, so 7 is ★★★½.
+// Use the From methods to read one from the different ways Letterboxd shows
+// ratings in its pages
+type Rating int
+
+// FromGlyphs sets the rating from star glyphs, like '★★★½'
+func (r *Rating) FromGlyphs(val string) error {
+	val = strings.TrimSpace(val)
+	if val == "" {
+		return ErrUnrated
+	}
+	var halfStars int
+	for _, c := range val {
+		switch c {
+		case '★':
+			halfStars += 2
+		case '½':
+			halfStars++
+		default:
+			return fmt.Errorf("invalid rating glyph: %q", c)
+		}
+	}
+	return r.set(halfStars)
+}
+
+// FromClass sets the rating from the class attribute of a rating span, like
+// 'rating -green rated-7'
+func (r *Rating) FromClass(val string) error {
+	for _, class := range strings.Fields(val) {
+		if strings.HasPrefix(class, "rated-") {
+			halfStars, err := strconv.Atoi(strings.TrimPrefix(class, "rated-"))
+			if err != nil {
+				return fmt.Errorf("invalid rating class: %v", class)
+			}
+			return r.set(halfStars)
+		}
+	}
+	return ErrUnrated
+}
+
+// FromAttr sets the rating from a numeric attribute, like data-rating="7"
+func (r *Rating) FromAttr(val string) error {
+	halfStars, err := strconv.Atoi(strings.TrimSpace(val))
+	if err != nil {
+		return fmt.Errorf("invalid rating attribute: %v", val)
+	}
+	return r.set(halfStars)
+}
+
+// set checks that halfStars is a real rating before using it. 0 is how
+// Letterboxd marks an unrated film
+func (r *Rating) set(halfStars int) error {
+	if halfStars == MinRating {
+		return ErrUnrated
+	}
+	if halfStars < MinRating || halfStars > MaxRating {
+		return fmt.Errorf("rating out of range: %v", halfStars)
+	}
+	*r = Rating(halfStars)
+	return nil
+}
+
+// Int returns the rating on the 0-10 scale
+func (r Rating) Int() int {
+	return int(r)
+}
+
+// Stars returns the rating in stars, from 0.5 to 5
+func (r Rating) Stars() float64 {
+	return float64(r) / 2
+}
+
+// String returns the rating as star glyphs
+func (r Rating) String() string {
+	s := strings.Repeat("★", int(r)/2)
+	if r%2 == 1 {
+		s += "½"
+	}
+	return s
+}
+
+// ptr returns the rating as an *int, for the Rating fields on entries
+func (r Rating) ptr() *int {
+	i := int(r)
+	return &i
+}
+
+// parseRating returns the rating on the 0-10 scale from either the numeric
+// form ('7') or the star glyphs ('★★★½'). A nil rating is returned when the
+// value is unrated, or can't be parsed
+func parseRating(val string) *int {
+	var r Rating
+	if err := r.FromAttr(val); err == nil {
+		return r.ptr()
+	}
+	if err := r.FromGlyphs(val); err == nil {
+		return r.ptr()
+	}
+	return nil
+}
+
+// ratingWithSelection returns the rating from a rating span, preferring the
+// rated-N class over the glyphs. Returns nil if there is no rating
+func ratingWithSelection(s *goquery.Selection) *int {
+	var r Rating
+	if err := r.FromClass(s.AttrOr("class", "")); err == nil {
+		return r.ptr()
+	}
+	return parseRating(s.Text())
+}
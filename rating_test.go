@@ -0,0 +1,121 @@
+package letterboxd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRatingFromGlyphs(t *testing.T) {
+	tests := map[string]struct {
+		val     string
+		want    Rating
+		wantErr error
+	}{
+		"half":       {val: "½", want: 1},
+		"stars":      {val: "★★★", want: 6},
+		"mixed":      {val: " ★★★½ ", want: 7},
+		"five-stars": {val: "★★★★★", want: 10},
+		"empty":      {val: "", wantErr: ErrUnrated},
+		"too-many":   {val: "★★★★★★"},
+		"garbage":    {val: "great"},
+	}
+	for desc, tt := range tests {
+		var got Rating
+		err := got.FromGlyphs(tt.val)
+		switch {
+		case tt.wantErr != nil:
+			require.ErrorIs(t, err, tt.wantErr, desc)
+		case tt.want == 0:
+			require.Error(t, err, desc)
+		default:
+			require.NoError(t, err, desc)
+			require.Equal(t, tt.want, got, desc)
+		}
+	}
+}
+
+func TestRatingFromClass(t *testing.T) {
+	tests := map[string]struct {
+		val     string
+		want    Rating
+		wantErr error
+	}{
+		"only":       {val: "rated-7", want: 7},
+		"with-other": {val: "rating -green rated-9", want: 9},
+		"unrated":    {val: "rating rated-0", wantErr: ErrUnrated},
+		"missing":    {val: "rating -green", wantErr: ErrUnrated},
+		"empty":      {val: "", wantErr: ErrUnrated},
+		"bad-number": {val: "rating rated-x"},
+		"too-high":   {val: "rating rated-11"},
+	}
+	for desc, tt := range tests {
+		var got Rating
+		err := got.FromClass(tt.val)
+		switch {
+		case tt.wantErr != nil:
+			require.ErrorIs(t, err, tt.wantErr, desc)
+		case tt.want == 0:
+			require.Error(t, err, desc)
+		default:
+			require.NoError(t, err, desc)
+			require.Equal(t, tt.want, got, desc)
+		}
+	}
+}
+
+func TestRatingFromAttr(t *testing.T) {
+	tests := map[string]struct {
+		val     string
+		want    Rating
+		wantErr error
+	}{
+		"numeric":  {val: "7", want: 7},
+		"spaces":   {val: " 10 ", want: 10},
+		"unrated":  {val: "0", wantErr: ErrUnrated},
+		"negative": {val: "-2"},
+		"too-high": {val: "11"},
+		"glyphs":   {val: "★★★"},
+		"empty":    {val: ""},
+	}
+	for desc, tt := range tests {
+		var got Rating
+		err := got.FromAttr(tt.val)
+		switch {
+		case tt.wantErr != nil:
+			require.ErrorIs(t, err, tt.wantErr, desc)
+		case tt.want == 0:
+			require.Error(t, err, desc)
+		default:
+			require.NoError(t, err, desc)
+			require.Equal(t, tt.want, got, desc)
+		}
+	}
+}
+
+func TestRatingFromErrorKeepsValue(t *testing.T) {
+	r := Rating(4)
+	require.Error(t, r.FromAttr("great"))
+	require.Equal(t, Rating(4), r)
+}
+
+func TestRatingConversions(t *testing.T) {
+	r := Rating(7)
+	require.Equal(t, 7, r.Int())
+	require.Equal(t, 3.5, r.Stars())
+	require.Equal(t, "★★★½", r.String())
+	require.Equal(t, "★★★★★", Rating(10).String())
+	require.Equal(t, "½", Rating(1).String())
+}
+
+func TestRatingWithSelection(t *testing.T) {
+	// The class wins over the glyphs
+	sel := selectWithString(`<span class="rating -green rated-9"> ★★★★ </span>`)
+	require.Equal(t, intPtr(9), ratingWithSelection(sel.Find("span")))
+
+	// Falls back to the glyphs without a rated class
+	sel = selectWithString(`<span class="rating"> ★★½ </span>`)
+	require.Equal(t, intPtr(5), ratingWithSelection(sel.Find("span")))
+
+	require.Nil(t, ratingWithSelection(selectWithString(`<p></p>`).Find("span")))
+}
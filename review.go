@@ -0,0 +1,295 @@
+package letterboxd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// maxReviews is the most reviews streamed for a single film, unless changed
+// with WithMaxReviews
+const maxReviews = 500
+
+// ReviewService is the interface for reading reviews
+type ReviewService interface {
+	StreamFilmReviews(context.Context, string, chan *Review, chan error)
+	PopularReviews(context.Context, string) ([]*Review, error)
+	Get(context.Context, string) (*Review, error)
+}
+
+// ReviewServiceOp is the operator for the ReviewService
+type ReviewServiceOp struct {
+	client *Client
+}
+
+// Review is a single members review of a film
+type Review struct {
+	ViewingID string     `json:"viewing_id"`
+	Username  string     `json:"username"`
+	Film      *Film      `json:"film"`
+	Rating    *int       `json:"rating,omitempty"` // 0-10 rating scale, nil if the review has no rating
+	Text      string     `json:"text"`             // Review text. Long reviews are cut short by Letterboxd
+	Spoiler   bool       `json:"spoiler"`          // The reviewer marked the review as containing spoilers
+	Likes     int        `json:"likes"`
+	Date      *time.Time `json:"date,omitempty"`
+}
+
+// ExtractReviews returns the reviews from a page of film reviews
+func ExtractReviews(r io.Reader) (interface{}, *Pagination, error) {
+	doc, err := newDocumentFromReader(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	reviews := []*Review{}
+	doc.Find("li.film-detail").Each(func(i int, s *goquery.Selection) {
+		if review := reviewWithSelection(s); review != nil {
+			reviews = append(reviews, review)
+		}
+	})
+	return reviews, paginationOrSinglePage(doc), nil
+}
+
+// reviewWithSelection returns a Review from a single film-detail item, or nil
+// if there is no reviewer
+func reviewWithSelection(s *goquery.Selection) *Review {
+	username := strings.Trim(s.Find("a.avatar").AttrOr("href", ""), "/")
+	if username == "" {
+		return nil
+	}
+	review := &Review{
+		ViewingID: strings.TrimPrefix(s.Find(`[data-likeable-uid^="viewing:"]`).AttrOr("data-likeable-uid", ""), "viewing:"),
+		Username:  username,
+		Rating:    ratingWithSelection(s.Find("span.rating").First()),
+	}
+	review.Text, review.Spoiler = reviewTextWithSelection(s.Find("div.body-text").First())
+	// Context links look like /karsten/film/cure/
+	parts := strings.Split(strings.Trim(s.Find("a.context").AttrOr("href", ""), "/"), "/")
+	if len(parts) >= 3 && parts[len(parts)-2] == "film" {
+		review.Film = &Film{Slug: parts[len(parts)-1]}
+	}
+	countS := s.Find(`[data-likeable-uid^="viewing:"]`).AttrOr("data-count", "")
+	if count, err := strconv.Atoi(strings.ReplaceAll(countS, ",", "")); err == nil {
+		review.Likes = count
+	}
+	if date, err := time.Parse("02 Jan 2006", strings.TrimSpace(s.Find("span.date").Text())); err == nil {
+		review.Date = &date
+	}
+	return review
+}
+
+// reviewTextWithSelection returns the text of a review body, and whether it is
+// marked as containing spoilers. The spoiler warning is left out of the text
+func reviewTextWithSelection(body *goquery.Selection) (string, bool) {
+	warning := body.Find("div.contains-spoilers")
+	if warning.Length() == 0 {
+		return strings.TrimSpace(body.Text()), false
+	}
+	body = body.Clone()
+	body.Find("div.contains-spoilers").Remove()
+	return strings.TrimSpace(body.Text()), true
+}
+
+// ExtractReview returns the review from a members review page
+func ExtractReview(r io.Reader) (interface{}, *Pagination, error) {
+	doc, err := newDocumentFromReader(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	body := doc.Find("div.review.body-text").First()
+	if body.Length() == 0 {
+		return nil, nil, errors.New("no review found")
+	}
+	review := &Review{
+		ViewingID: strings.TrimPrefix(doc.Find(`[data-likeable-uid^="viewing:"]`).AttrOr("data-likeable-uid", ""), "viewing:"),
+		Rating:    ratingWithSelection(doc.Find("span.rating").First()),
+	}
+	review.Text, review.Spoiler = reviewTextWithSelection(body)
+	countS := doc.Find(`[data-likeable-uid^="viewing:"]`).AttrOr("data-count", "")
+	if count, err := strconv.Atoi(strings.ReplaceAll(countS, ",", "")); err == nil {
+		review.Likes = count
+	}
+	return review, nil, nil
+}
+
+// Get returns a single review from the path of its page, like
+// /someguy/film/cure/. The review text is in full, unlike StreamFilmReviews
+func (r *ReviewServiceOp) Get(ctx context.Context, path string) (*Review, error) {
+	// Review paths look like /someguy/film/cure/, with a viewing number on
+	// the end for films reviewed more than once
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) < 3 || parts[1] != "film" {
+		return nil, fmt.Errorf("not a review path: %v", path)
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/%s/", r.client.baseURL, strings.Join(parts, "/")), nil)
+	if err != nil {
+		return nil, err
+	}
+	item, resp, err := r.client.sendRequest(req, ExtractReview)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Response != nil {
+		dclose(resp.Body)
+	}
+	review, ok := item.Data.(*Review)
+	if !ok {
+		return nil, errors.New("unexpected data type for review")
+	}
+	review.Username = parts[0]
+	review.Film = &Film{Slug: parts[2]}
+	return review, nil
+}
+
+// reviewsWithPath returns a single page of reviews
+func (r *ReviewServiceOp) reviewsWithPath(ctx context.Context, slug string, page int) ([]*Review, *Pagination, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/film/%s/reviews/by/activity/page/%d/", r.client.baseURL, slug, page), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	items, resp, err := r.client.sendRequest(req, ExtractReviews)
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.Response != nil {
+		dclose(resp.Body)
+	}
+	reviews, ok := items.Data.([]*Review)
+	if !ok {
+		return nil, nil, errors.New("unexpected data type for reviews")
+	}
+	return reviews, &items.Pagination, nil
+}
+
+// reviewPage is a single page of reviews fetched by StreamFilmReviews
+type reviewPage struct {
+	reviews []*Review
+	err     error
+}
+
+// StreamFilmReviews streams the reviews of a film, most active first. The
+// first page is fetched on its own, then the rest are fetched concurrently,
+// bounded by the clients MaxConcurrentPages, and sent in page order. Only the
+// first 50 pages are read, and streaming stops once the clients max reviews
+// have been sent, see WithMaxReviews
+func (r *ReviewServiceOp) StreamFilmReviews(ctx context.Context, slug string, rchan chan *Review, done chan error) {
+	slug, err := SlugFromURL(slug)
+	if err != nil {
+		done <- err
+		return
+	}
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var sent int
+	// send returns false once no more reviews should be sent
+	send := func(reviews []*Review) bool {
+		for _, review := range reviews {
+			select {
+			case rchan <- review:
+			case <-ctx.Done():
+				return false
+			}
+			sent++
+			if sent >= r.client.maxReviews {
+				return false
+			}
+		}
+		return true
+	}
+
+	firstReviews, pagination, err := r.reviewsWithPath(ctx, slug, 1)
+	if err != nil {
+		done <- err
+		return
+	}
+	if !send(firstReviews) {
+		done <- parent.Err()
+		return
+	}
+
+	lastPage := min(pagination.TotalPages, maxPages)
+	guard := make(chan struct{}, max(r.client.MaxConcurrentPages, 1))
+	// Each page gets its own slot, so pages fetched out of order can still be
+	// sent in order
+	pages := make([]chan reviewPage, lastPage+1)
+	var wg sync.WaitGroup
+	for page := 2; page <= lastPage; page++ {
+		pages[page] = make(chan reviewPage, 1)
+		wg.Add(1)
+		go func(page int) {
+			defer wg.Done()
+			select {
+			case guard <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-guard }()
+			reviews, _, err := r.reviewsWithPath(ctx, slug, page)
+			if err != nil {
+				err = fmt.Errorf("page %v: %w", page, err)
+			}
+			pages[page] <- reviewPage{reviews: reviews, err: err}
+		}(page)
+	}
+	// Stop the fetches still going before returning
+	defer wg.Wait()
+	defer cancel()
+	for page := 2; page <= lastPage; page++ {
+		var result reviewPage
+		select {
+		case result = <-pages[page]:
+		case <-ctx.Done():
+			done <- parent.Err()
+			return
+		}
+		if result.err != nil {
+			done <- result.err
+			return
+		}
+		if !send(result.reviews) {
+			break
+		}
+	}
+	// Hitting the cap stops the stream early, but that is not an error for the
+	// caller
+	done <- parent.Err()
+}
+
+// PopularReviews returns the most popular reviews of a film, which is the first
+// page of StreamFilmReviews
+func (r *ReviewServiceOp) PopularReviews(ctx context.Context, slug string) ([]*Review, error) {
+	slug, err := SlugFromURL(slug)
+	if err != nil {
+		return nil, err
+	}
+	reviews, _, err := r.reviewsWithPath(ctx, slug, 1)
+	if err != nil {
+		return nil, err
+	}
+	return reviews, nil
+}
+
+// SlurpReviews collects the reviews from StreamFilmReviews into a slice
+func SlurpReviews(reviewC chan *Review, doneC chan error) ([]*Review, error) {
+	var ret []*Review
+	for {
+		select {
+		case review := <-reviewC:
+			ret = append(ret, review)
+		case err := <-doneC:
+			if err != nil {
+				return nil, err
+			}
+			return ret, nil
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package letterboxd
+
+import (
+	"context"
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractReviews(t *testing.T) {
+	f, err := os.Open("testdata/film/reviews/1.html")
+	require.NoError(t, err)
+	defer f.Close()
+	items, pagination, err := ExtractReviews(f)
+	require.NoError(t, err)
+	require.Equal(t, 2, pagination.TotalPages)
+	reviews := items.([]*Review)
+	require.Equal(t, 3, len(reviews))
+	require.Equal(t, "1001", reviews[0].ViewingID)
+	require.Equal(t, "karsten", reviews[0].Username)
+	require.Equal(t, "cure", reviews[0].Film.Slug)
+	require.Equal(t, 9, *reviews[0].Rating)
+	require.Equal(t, "The sound design alone.", reviews[0].Text)
+	require.Equal(t, 1204, reviews[0].Likes)
+	require.Equal(t, "2023-01-02", reviews[0].Date.Format("2006-01-02"))
+}
+
+func TestExtractReviewsSpoilers(t *testing.T) {
+	f, err := os.Open("testdata/film/reviews/2.html")
+	require.NoError(t, err)
+	defer f.Close()
+	items, _, err := ExtractReviews(f)
+	require.NoError(t, err)
+	reviews := items.([]*Review)
+	require.Equal(t, "lucy", reviews[0].Username)
+	require.False(t, reviews[0].Spoiler)
+	require.Equal(t, "sam", reviews[1].Username)
+	require.True(t, reviews[1].Spoiler)
+	require.Equal(t, "I get it, I just did not love it.", reviews[1].Text)
+}
+
+func TestPopularReviews(t *testing.T) {
+	reviews, err := sc.Review.PopularReviews(context.TODO(), "cure")
+	require.NoError(t, err)
+	var got []string
+	for _, review := range reviews {
+		got = append(got, review.Username)
+	}
+	require.Equal(t, []string{"karsten", "mia", "jay"}, got)
+
+	_, err = sc.Review.PopularReviews(context.TODO(), "/someguy/list/foo/")
+	require.Error(t, err)
+}
+
+func TestStreamFilmReviews(t *testing.T) {
+	reviewC := make(chan *Review)
+	doneC := make(chan error)
+	go sc.Review.StreamFilmReviews(context.TODO(), "cure", reviewC, doneC)
+	reviews, err := SlurpReviews(reviewC, doneC)
+	require.NoError(t, err)
+	var got []string
+	for _, review := range reviews {
+		got = append(got, review.Username)
+	}
+	sort.Strings(got)
+	require.Equal(t, []string{"jay", "karsten", "lucy", "mia", "sam"}, got)
+}
+
+func TestStreamFilmReviewsOrder(t *testing.T) {
+	reviewC := make(chan *Review)
+	doneC := make(chan error)
+	go sc.Review.StreamFilmReviews(context.TODO(), "orderedreviews", reviewC, doneC)
+	reviews, err := SlurpReviews(reviewC, doneC)
+	require.NoError(t, err)
+	var got []string
+	for _, review := range reviews {
+		got = append(got, review.Username)
+	}
+	require.Equal(t, []string{"karsten", "mia", "jay", "lucy", "sam", "ana", "ben"}, got)
+}
+
+func TestStreamFilmReviewsPageError(t *testing.T) {
+	reviewC := make(chan *Review)
+	doneC := make(chan error)
+	go sc.Review.StreamFilmReviews(context.TODO(), "brokenreviews", reviewC, doneC)
+	_, err := SlurpReviews(reviewC, doneC)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "page 2")
+}
+
+func TestStreamFilmReviewsMax(t *testing.T) {
+	c := New(WithNoCache(), WithBaseURL(srv.URL), WithMaxReviews(4))
+	reviewC := make(chan *Review)
+	doneC := make(chan error)
+	go c.Review.StreamFilmReviews(context.TODO(), "cure", reviewC, doneC)
+	reviews, err := SlurpReviews(reviewC, doneC)
+	require.NoError(t, err)
+	require.Equal(t, 4, len(reviews))
+
+	// Anything less than 1 keeps the default
+	c = New(WithNoCache(), WithBaseURL(srv.URL), WithMaxReviews(0))
+	require.Equal(t, maxReviews, c.maxReviews)
+	go c.Review.StreamFilmReviews(context.TODO(), "cure", reviewC, doneC)
+	reviews, err = SlurpReviews(reviewC, doneC)
+	require.NoError(t, err)
+	require.Equal(t, 5, len(reviews))
+}
+
+func TestReviewGet(t *testing.T) {
+	review, err := sc.Review.Get(context.TODO(), "/pstinnett/film/cure/")
+	require.NoError(t, err)
+	require.Equal(t, "pstinnett", review.Username)
+	require.Equal(t, "cure", review.Film.Slug)
+	require.Equal(t, intPtr(7), review.Rating)
+	require.Contains(t, review.Text, "Rewatched and it only got worse")
+
+	_, err = sc.Review.Get(context.TODO(), "/film/cure/")
+	require.EqualError(t, err, "not a review path: /film/cure/")
+}
@@ -0,0 +1,65 @@
+package letterboxd
+
+import "time"
+
+// UserSnapshot is a point in time capture of a users films
+type UserSnapshot struct {
+	Username  string       `json:"username"`
+	Taken     time.Time    `json:"taken"`
+	Watched   FilmSet      `json:"watched"`
+	WatchList FilmSet      `json:"watchlist"`
+	Diary     DiaryEntries `json:"diary"`
+}
+
+// SnapshotDiff is what changed between two UserSnapshots
+type SnapshotDiff struct {
+	NewlyWatched     FilmSet      `json:"newly_watched"`
+	WatchListAdded   FilmSet      `json:"watchlist_added"`
+	WatchListRemoved FilmSet      `json:"watchlist_removed"`
+	NewDiaryEntries  DiaryEntries `json:"new_diary_entries"`
+}
+
+// DiffSnapshots returns what changed between an old and a new snapshot of a
+// user. Films are matched by slug, and diary entries by their viewing ID. A
+// nil snapshot is treated as empty
+func DiffSnapshots(oldSnap, newSnap *UserSnapshot) *SnapshotDiff {
+	if oldSnap == nil {
+		oldSnap = &UserSnapshot{}
+	}
+	if newSnap == nil {
+		newSnap = &UserSnapshot{}
+	}
+	return &SnapshotDiff{
+		NewlyWatched:     filmsMissingFrom(newSnap.Watched, oldSnap.Watched),
+		WatchListAdded:   filmsMissingFrom(newSnap.WatchList, oldSnap.WatchList),
+		WatchListRemoved: filmsMissingFrom(oldSnap.WatchList, newSnap.WatchList),
+		NewDiaryEntries:  diaryEntriesMissingFrom(newSnap.Diary, oldSnap.Diary),
+	}
+}
+
+// filmsMissingFrom returns the films in a that are not in b
+func filmsMissingFrom(a, b FilmSet) FilmSet {
+	existing := b.BySlug()
+	ret := FilmSet{}
+	for _, film := range a {
+		if _, ok := existing[film.Slug]; !ok {
+			ret = append(ret, film)
+		}
+	}
+	return ret
+}
+
+// diaryEntriesMissingFrom returns the entries in a that are not in b
+func diaryEntriesMissingFrom(a, b DiaryEntries) DiaryEntries {
+	existing := map[string]struct{}{}
+	for _, entry := range b {
+		existing[entry.ViewingID] = struct{}{}
+	}
+	ret := DiaryEntries{}
+	for _, entry := range a {
+		if _, ok := existing[entry.ViewingID]; !ok {
+			ret = append(ret, entry)
+		}
+	}
+	return ret
+}
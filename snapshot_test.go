@@ -0,0 +1,47 @@
+package letterboxd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	oldSnap := &UserSnapshot{
+		Username:  "someguy",
+		Watched:   FilmSet{{Slug: "cure"}, {Slug: "nope"}},
+		WatchList: FilmSet{{Slug: "parasite-2019"}, {Slug: "come-and-see"}},
+		Diary:     DiaryEntries{{ViewingID: "1"}, {ViewingID: "2"}},
+	}
+	newSnap := &UserSnapshot{
+		Username:  "someguy",
+		Watched:   FilmSet{{Slug: "cure"}, {Slug: "nope"}, {Slug: "come-and-see"}},
+		WatchList: FilmSet{{Slug: "parasite-2019"}, {Slug: "barbarian-2022"}},
+		Diary:     DiaryEntries{{ViewingID: "1"}, {ViewingID: "2"}, {ViewingID: "3"}},
+	}
+	got := DiffSnapshots(oldSnap, newSnap)
+	require.Equal(t, FilmSet{{Slug: "come-and-see"}}, got.NewlyWatched)
+	require.Equal(t, FilmSet{{Slug: "barbarian-2022"}}, got.WatchListAdded)
+	require.Equal(t, FilmSet{{Slug: "come-and-see"}}, got.WatchListRemoved)
+	require.Equal(t, DiaryEntries{{ViewingID: "3"}}, got.NewDiaryEntries)
+}
+
+func TestDiffSnapshotsNoChange(t *testing.T) {
+	snap := &UserSnapshot{
+		Watched: FilmSet{{Slug: "cure"}},
+		Diary:   DiaryEntries{{ViewingID: "1"}},
+	}
+	got := DiffSnapshots(snap, snap)
+	require.Empty(t, got.NewlyWatched)
+	require.Empty(t, got.WatchListAdded)
+	require.Empty(t, got.WatchListRemoved)
+	require.Empty(t, got.NewDiaryEntries)
+}
+
+func TestDiffSnapshotsNil(t *testing.T) {
+	got := DiffSnapshots(nil, &UserSnapshot{Watched: FilmSet{{Slug: "cure"}}})
+	require.Equal(t, 1, len(got.NewlyWatched))
+
+	got = DiffSnapshots(&UserSnapshot{WatchList: FilmSet{{Slug: "cure"}}}, nil)
+	require.Equal(t, 1, len(got.WatchListRemoved))
+}
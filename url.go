@@ -1,9 +1,12 @@
 package letterboxd
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"net/http"
 	"net/url"
 	"strings"
 )
@@ -11,6 +14,7 @@ import (
 // URLService is an interface for defining methods on a URL
 type URLService interface {
 	Items(ctx context.Context, url string) (interface{}, error)
+	List(ctx context.Context, url string) (*List, FilmSet, error)
 }
 
 // URLServiceOp is the operator for an URLService
@@ -77,6 +81,102 @@ func (u *URLServiceOp) Items(ctx context.Context, lurl string) (interface{}, err
 	return nil, errors.New("could not find a match for that URL")
 }
 
+// List returns the metadata and films of the list at the given URL
+func (u *URLServiceOp) List(ctx context.Context, lurl string) (*List, FilmSet, error) {
+	id, err := listIDWithURL(lurl)
+	if err != nil {
+		return nil, nil, err
+	}
+	// The first page carries the metadata, so stream the rest of the list from it
+	list, firstFilms, pagination, err := u.client.listFirstPage(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := u.client.Film.EnhanceFilmList(ctx, &firstFilms); err != nil {
+		return nil, nil, err
+	}
+	filmC := make(chan *Film)
+	errorC := make(chan error)
+	go u.client.streamPagesFrom(ctx, func(page int) string {
+		return u.client.listPageURL(id, page)
+	}, firstFilms, pagination, filmC, errorC)
+	films, err := SlurpFilms(filmC, errorC)
+	if err != nil {
+		return nil, nil, err
+	}
+	return list, films, nil
+}
+
+// listIDWithURL returns the ListID for a list URL, like
+// https://letterboxd.com/dave/list/official-top-250-narrative-feature-films/
+func listIDWithURL(lurl string) (*ListID, error) {
+	path, err := normalizeURLPath(lurl)
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.Split(path, "/")
+	if len(parts) < 4 || parts[2] != "list" {
+		return nil, errors.New("not a list URL")
+	}
+	return &ListID{User: parts[1], Slug: parts[3]}, nil
+}
+
+// listPageURL returns the URL of a single page of a list
+func (c *Client) listPageURL(id *ListID, page int) string {
+	return fmt.Sprintf("%s/%s/list/%s/page/%v/", c.baseURL, id.User, id.Slug, page)
+}
+
+// listPage is the metadata and film previews from a single page of a list
+type listPage struct {
+	List  *List
+	Films FilmSet
+}
+
+// extractListPage returns a listPage from an io.Reader
+func extractListPage(r io.Reader) (interface{}, *Pagination, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	films, pagination, err := ExtractUserFilms(bytes.NewReader(b))
+	if err != nil {
+		return nil, nil, err
+	}
+	list, _, err := ExtractListMetadata(bytes.NewReader(b))
+	if err != nil {
+		return nil, nil, err
+	}
+	return &listPage{List: list.(*List), Films: films.(FilmSet)}, pagination, nil
+}
+
+// listFirstPage returns the List metadata, along with the film previews and
+// pagination from the first page of a list
+func (c *Client) listFirstPage(ctx context.Context, id *ListID) (*List, FilmSet, *Pagination, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.listPageURL(id, 1), nil)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	pData, resp, err := c.sendRequest(req, extractListPage)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if resp.Response != nil {
+		dclose(resp.Body)
+	}
+	page, ok := pData.Data.(*listPage)
+	if !ok {
+		return nil, nil, nil, errors.New("unexpected data type for list")
+	}
+	list := page.List
+	if list.Owner == "" {
+		list.Owner = id.User
+	}
+	if list.Slug == "" {
+		list.Slug = id.Slug
+	}
+	return list, page.Films, &pData.Pagination, nil
+}
+
 func normalizeURLPath(ourl string) (string, error) {
 	ourl = strings.TrimSuffix(ourl, "/")
 	if strings.HasPrefix(ourl, "/") {
@@ -3,6 +3,7 @@ package letterboxd
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -33,6 +34,37 @@ func TestURLList(t *testing.T) {
 	require.Greater(t, len(items.(FilmSet)), 0)
 }
 
+func TestURLListMetadata(t *testing.T) {
+	list, films, err := sc.URL.List(context.TODO(), "https://letterboxd.com/dave/list/official-top-250-narrative-feature-films/")
+	require.NoError(t, err)
+	require.Equal(t, "Official Top 250 Narrative Feature Films", list.Title)
+	require.Equal(t, "dave", list.Owner)
+	require.Equal(t, "official-top-250-narrative-feature-films", list.Slug)
+	require.True(t, list.Ranked)
+	require.Equal(t, ListVisibilityPublic, list.Visibility)
+	require.Equal(t, "2013-11-08T10:38:22Z", list.Created.Format(time.RFC3339))
+	require.Greater(t, len(films), 0)
+}
+
+func TestURLListFetchesFirstPageOnce(t *testing.T) {
+	tr := &countingTransport{prefix: "/dave/list/official-top-250-narrative-feature-films/page/1/"}
+	c := New(WithNoCache(), WithBaseURL(srv.URL))
+	c.client.Transport = tr
+
+	_, films, err := c.URL.List(context.TODO(), "/dave/list/official-top-250-narrative-feature-films/")
+	require.NoError(t, err)
+	require.Equal(t, 1, tr.count)
+
+	items, err := sc.URL.Items(context.TODO(), "/dave/list/official-top-250-narrative-feature-films")
+	require.NoError(t, err)
+	require.Equal(t, len(items.(FilmSet)), len(films))
+}
+
+func TestURLListNotAList(t *testing.T) {
+	_, _, err := sc.URL.List(context.TODO(), "/singleguy/films")
+	require.EqualError(t, err, "not a list URL")
+}
+
 func TestURLFilms(t *testing.T) {
 	items, err := sc.URL.Items(context.TODO(), "/singleguy/films")
 	require.NoError(t, err)
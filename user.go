@@ -3,15 +3,19 @@ package letterboxd
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
+	"unicode"
 
 	"github.com/PuerkitoBio/goquery"
 )
@@ -20,27 +24,88 @@ import (
 type UserService interface {
 	Exists(context.Context, string) (bool, error)
 	Profile(context.Context, string) (*User, *Response, error)
+	RatingDistribution(context.Context, string) (map[float64]int, error)
+	RatingsPage(context.Context, string) (map[float64]int, *Pagination, error)
 	Following(context.Context, string) ([]string, *Response, error)
 	Followers(context.Context, string) ([]string, *Response, error)
+	NetworkCounts(context.Context, string) (*NetworkCounts, error)
+	Mutuals(context.Context, string) ([]string, error)
+	UnwatchedFromList(context.Context, string, *ListID) (FilmSet, error)
 	// Interact with Diary
 	StreamDiary(context.Context, string, chan *DiaryEntry, chan error)
+	StreamDiaryWithOpts(context.Context, string, DiaryOpts, chan *DiaryEntry, chan error)
 	Diary(context.Context, string) (DiaryEntries, error)
+	DiaryWithOpts(context.Context, string, DiaryOpts) (DiaryEntries, error)
+	DiaryFromPage(context.Context, string, int) (DiaryEntries, *Pagination, error)
+	DiaryPage(context.Context, string, int) (DiaryEntries, *Pagination, error)
+	WatchedSince(context.Context, string, time.Time) (FilmSet, error)
 	MustDiary(context.Context, string) DiaryEntries
 
 	StreamList(context.Context, string, string, chan *Film, chan error)
 	StreamWatched(context.Context, string, chan *Film, chan error)
 	StreamWatchList(context.Context, string, chan *Film, chan error)
 	WatchList(context.Context, string) (FilmSet, *Response, error)
+	WatchListBy(context.Context, string, string) (FilmSet, *Response, error)
 	ExtractDiaryEntries(io.Reader) (interface{}, *Pagination, error)
+	StreamActivity(context.Context, string, chan *ActivityItem, chan error)
+	FollowingWatched(context.Context, string, chan *ActivityItem, chan error)
+	LikedLists(context.Context, string) ([]*List, error)
+	WatchListCount(context.Context, string) (int, error)
+	WatchlistProgress(context.Context, string) (int, int, error)
 }
 
 // User represents a Letterboxd user
 type User struct {
-	Username         string   `json:"username"`
-	Bio              string   `json:"bio,omitempty"`
-	WatchedFilmCount int      `json:"watched_film_count"`
-	Following        []string `json:"following"`
-	Followers        []string `json:"followers"`
+	Username         string          `json:"username"`
+	RenamedFrom      string          `json:"renamed_from,omitempty"` // The username asked for, when the profile turned out to be a renamed account
+	GivenName        string          `json:"given_name,omitempty"`   // The name shown on the profile, empty when it is just the username
+	Pronouns         string          `json:"pronouns,omitempty"`
+	Bio              string          `json:"bio,omitempty"`
+	AvatarURL        string          `json:"avatar_url,omitempty"`
+	Links            []string        `json:"links,omitempty"`   // Websites and accounts from the profile and bio
+	Socials          []SocialAccount `json:"socials,omitempty"` // The Links that are recognizable social accounts
+	WatchedFilmCount int             `json:"watched_film_count"`
+	Following        []string        `json:"following"`
+	Followers        []string        `json:"followers"`
+	// ParseWarnings are the parts of the profile page that could not be found.
+	// With WithStrictExtraction, any of these fail the request instead
+	ParseWarnings []string `json:"parse_warnings,omitempty"`
+}
+
+// Social networks that are recognized in profile links
+const (
+	SocialNetworkTwitter   = "twitter"
+	SocialNetworkInstagram = "instagram"
+	SocialNetworkFacebook  = "facebook"
+	SocialNetworkTikTok    = "tiktok"
+	SocialNetworkYouTube   = "youtube"
+)
+
+// socialNetworkHosts maps the hosts of social account links to their network
+var socialNetworkHosts = map[string]string{
+	"twitter.com":   SocialNetworkTwitter,
+	"x.com":         SocialNetworkTwitter,
+	"instagram.com": SocialNetworkInstagram,
+	"facebook.com":  SocialNetworkFacebook,
+	"tiktok.com":    SocialNetworkTikTok,
+	"youtube.com":   SocialNetworkYouTube,
+}
+
+// SocialAccount is a members account on another site, from their profile
+type SocialAccount struct {
+	Network string `json:"network"` // One of the SocialNetwork constants
+	Handle  string `json:"handle"`
+	URL     string `json:"url"`
+}
+
+func (u *User) extractionWarnings() []string {
+	return u.ParseWarnings
+}
+
+// NetworkCounts are the totals shown in the header of a users network pages
+type NetworkCounts struct {
+	Following int `json:"following"`
+	Followers int `json:"followers"`
 }
 
 // UserServiceOp is the operator for the UserService
@@ -59,8 +124,11 @@ func ExtractPeople(r io.Reader) (interface{}, *Pagination, error) {
 	if err != nil {
 		return nil, nil, err
 	}
-	hasNext := hasNext(bytes.NewReader(body))
-	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	hasNext, err := hasNext(bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, err
+	}
+	doc, err := newDocumentFromReader(bytes.NewReader(body))
 	if err != nil {
 		return nil, nil, err
 	}
@@ -79,7 +147,7 @@ func ExtractPeople(r io.Reader) (interface{}, *Pagination, error) {
 
 // ExtractUser returns a user from a given io.Reader
 func ExtractUser(r io.Reader) (interface{}, *Pagination, error) {
-	doc, err := goquery.NewDocumentFromReader(r)
+	doc, err := newDocumentFromReader(r)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -89,18 +157,40 @@ func ExtractUser(r io.Reader) (interface{}, *Pagination, error) {
 			user.Bio = strings.TrimSpace(s.Text())
 		})
 	})
+	user.AvatarURL = doc.Find("div.profile-avatar img").First().AttrOr("src", "")
+	doc.Find("div.profile-metadata a.metadatum, section#person-bio div.collapsible-text a").Each(func(i int, s *goquery.Selection) {
+		href := strings.TrimSpace(s.AttrOr("href", ""))
+		if !strings.HasPrefix(href, "http://") && !strings.HasPrefix(href, "https://") {
+			return
+		}
+		if stringInSlice(href, user.Links) {
+			return
+		}
+		user.Links = append(user.Links, href)
+		if social := socialAccountWithURL(href); social != nil {
+			user.Socials = append(user.Socials, *social)
+		}
+	})
 	doc.Find("section.js-profile-header").Each(func(i int, s *goquery.Selection) {
 		user.Username = s.AttrOr("data-person", "")
 	})
+	nameWrap := doc.Find("div.profile-name-wrap").First()
+	// Profiles without a name set show the username instead
+	if name := strings.TrimSpace(nameWrap.Find("h1").First().Text()); name != user.Username {
+		user.GivenName = name
+	}
+	user.Pronouns = strings.TrimSpace(nameWrap.Find("span.pronoun").First().Text())
+	foundFilmCount := false
 	doc.Find("div.profile-stats").Each(func(i int, s *goquery.Selection) {
 		s.Find("a").Each(func(i int, s *goquery.Selection) {
-			if s.AttrOr("href", "") == fmt.Sprintf("/%v/films/", user.Username) {
+			if isFilmsStatLink(s.AttrOr("href", ""), user.Username) {
 				s.Find("span.value").Each(func(i int, s *goquery.Selection) {
 					countS := strings.TrimSpace(s.Text())
 					countS = strings.ReplaceAll(countS, ",", "")
 					count, err := strconv.Atoi(countS)
 					if err == nil {
 						user.WatchedFilmCount = count
+						foundFilmCount = true
 					}
 				})
 				// user.WatchedFilmCount, _ = s.AttrOr("data-count", "").Atoi()
@@ -108,12 +198,182 @@ func ExtractUser(r io.Reader) (interface{}, *Pagination, error) {
 		})
 	})
 	if user.Username == "" {
-		return nil, nil, fmt.Errorf("failed to extract user")
+		user.ParseWarnings = append(user.ParseWarnings, "no username found")
+	}
+	if !foundFilmCount {
+		user.ParseWarnings = append(user.ParseWarnings, "no film count stat found")
 	}
 	return user, nil, nil
 }
 
-// MustDiary See GetDiary, but will panic instead of returning an error
+// isFilmsStatLink returns true if href is the watched films link in the
+// profile stats, like '/dave/films/'. Any member matches when the username
+// could not be found
+func isFilmsStatLink(href, username string) bool {
+	if username != "" {
+		return href == fmt.Sprintf("/%v/films/", username)
+	}
+	parts := strings.Split(strings.Trim(href, "/"), "/")
+	return len(parts) == 2 && parts[1] == "films"
+}
+
+// socialAccountWithURL returns the SocialAccount for a profile link, or nil if
+// it is not a recognized social network
+func socialAccountWithURL(u string) *SocialAccount {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return nil
+	}
+	network, ok := socialNetworkHosts[strings.TrimPrefix(strings.ToLower(parsed.Hostname()), "www.")]
+	if !ok {
+		return nil
+	}
+	handle := strings.TrimPrefix(strings.Split(strings.Trim(parsed.Path, "/"), "/")[0], "@")
+	if handle == "" {
+		return nil
+	}
+	return &SocialAccount{Network: network, Handle: handle, URL: u}
+}
+
+// ExtractRatingDistribution returns the number of ratings a user has given at
+// each half-star, using the ratings histogram from an io.Reader
+func ExtractRatingDistribution(r io.Reader) (interface{}, *Pagination, error) {
+	doc, err := newDocumentFromReader(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	dist, err := ratingDistributionWithDoc(doc)
+	if err != nil {
+		return nil, nil, err
+	}
+	return dist, nil, nil
+}
+
+// ExtractRatingsPage returns the ratings histogram from a users
+// films/ratings/ page, along with the pagination of the rated films grid
+func ExtractRatingsPage(r io.Reader) (interface{}, *Pagination, error) {
+	doc, err := newDocumentFromReader(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	dist, err := ratingDistributionWithDoc(doc)
+	if err != nil {
+		return nil, nil, err
+	}
+	return dist, paginationOrSinglePage(doc), nil
+}
+
+// ratingDistributionWithDoc returns the number of ratings at each half-star
+// from the first ratings histogram in a goquery Doc
+func ratingDistributionWithDoc(doc *goquery.Document) (map[float64]int, error) {
+	dist := map[float64]int{}
+	doc.Find("div.rating-histogram").First().Find("li.rating-histogram-bar").Each(func(i int, s *goquery.Selection) {
+		a := s.Find("a").First()
+		parts := strings.Split(strings.TrimSuffix(a.AttrOr("href", ""), "/"), "/")
+		for idx, part := range parts {
+			if part != "rated" || idx+1 >= len(parts) {
+				continue
+			}
+			rating, err := ratingWithHistogramSegment(parts[idx+1])
+			if err != nil {
+				return
+			}
+			fields := strings.FieldsFunc(a.AttrOr("title", ""), unicode.IsSpace)
+			if len(fields) == 0 {
+				return
+			}
+			count, err := strconv.Atoi(strings.ReplaceAll(fields[0], ",", ""))
+			if err != nil {
+				return
+			}
+			dist[rating] = count
+		}
+	})
+	if len(dist) == 0 {
+		return nil, errors.New("no rating histogram found")
+	}
+	return dist, nil
+}
+
+// ratingWithHistogramSegment converts a histogram path segment like '3%C2%BD'
+// (3½) in to a float64 star rating. Film histograms use decimals instead, like
+// '3.5'
+func ratingWithHistogramSegment(seg string) (float64, error) {
+	seg, err := url.PathUnescape(seg)
+	if err != nil {
+		return 0, err
+	}
+	if strings.Contains(seg, ".") {
+		rating, err := strconv.ParseFloat(seg, 64)
+		if err != nil {
+			return 0, err
+		}
+		if rating*2 != float64(int(rating*2)) || rating*2 < 1 || rating*2 > MaxRating {
+			return 0, fmt.Errorf("rating out of range: %v", rating)
+		}
+		return rating, nil
+	}
+	var rating float64
+	if strings.HasSuffix(seg, "½") {
+		rating = 0.5
+		seg = strings.TrimSuffix(seg, "½")
+	}
+	if seg != "" {
+		stars, err := strconv.Atoi(seg)
+		if err != nil {
+			return 0, err
+		}
+		rating += float64(stars)
+	}
+	if rating*2 > MaxRating {
+		return 0, fmt.Errorf("rating out of range: %v", rating)
+	}
+	return rating, nil
+}
+
+// RatingDistribution returns the number of ratings a user has given at each
+// half-star. This comes from the histogram on the users profile, so it's much
+// cheaper than streaming the whole diary
+func (u *UserServiceOp) RatingDistribution(ctx context.Context, username string) (map[float64]int, error) {
+	username, err := normalizeUsername(username)
+	if err != nil {
+		return nil, err
+	}
+	req := mustNewGetRequest(fmt.Sprintf("%s/%s", u.client.baseURL, username))
+	pData, resp, err := u.client.sendRequest(req, ExtractRatingDistribution)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Response != nil {
+		defer dclose(resp.Body)
+	}
+	return pData.Data.(map[float64]int), nil
+}
+
+// RatingsPage returns the number of ratings a user has given at each
+// half-star, using the histogram on their films/ratings/ page. This is the
+// cheapest way to get a rating distribution, as it's a single request. The
+// Pagination is for the grid of rated films on the same page
+func (u *UserServiceOp) RatingsPage(ctx context.Context, username string) (map[float64]int, *Pagination, error) {
+	username, err := normalizeUsername(username)
+	if err != nil {
+		return nil, nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/%s/films/ratings/", u.client.baseURL, username), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	pData, resp, err := u.client.sendRequest(req, ExtractRatingsPage)
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.Response != nil {
+		defer dclose(resp.Body)
+	}
+	return pData.Data.(map[float64]int), &pData.Pagination, nil
+}
+
+// MustDiary See Diary, but will panic with the error instead of returning it
 func (u *UserServiceOp) MustDiary(ctx context.Context, username string) DiaryEntries {
 	items, err := u.Diary(ctx, username)
 	panicIfErr(err)
@@ -123,20 +383,25 @@ func (u *UserServiceOp) MustDiary(ctx context.Context, username string) DiaryEnt
 // Diary returns all diary entries for a given order, sorted by watched date,
 // with the most recent watches first
 func (u *UserServiceOp) Diary(ctx context.Context, username string) (DiaryEntries, error) {
+	return u.DiaryWithOpts(ctx, username, DefaultDiaryOpts())
+}
+
+// DiaryWithOpts is Diary, with control over what else is fetched for each
+// entry
+func (u *UserServiceOp) DiaryWithOpts(ctx context.Context, username string, opts DiaryOpts) (DiaryEntries, error) {
 	items := DiaryEntries{}
 	c := make(chan *DiaryEntry)
 	dc := make(chan error)
-	go u.StreamDiary(ctx, username, c, dc)
+	go u.StreamDiaryWithOpts(ctx, username, opts, c, dc)
 	for loop := true; loop; {
 		select {
 		case d := <-c:
 			items = append(items, d)
 		case err := <-dc:
 			if err != nil {
-				dc <- err
-			} else {
-				loop = false
+				return nil, err
 			}
+			loop = false
 		}
 	}
 	// Sort entries
@@ -146,64 +411,197 @@ func (u *UserServiceOp) Diary(ctx context.Context, username string) (DiaryEntrie
 	return items, nil
 }
 
-// StreamDiary streams a users diary in to the given channels
+// DiaryFromPage returns the diary entries for a given user, starting at
+// startPage and continuing through the last page. This allows resuming a large
+// diary scrape without re-fetching the earlier pages. The returned Pagination
+// is from the last page fetched, so its CurrentPage can be saved as a cursor
+func (u *UserServiceOp) DiaryFromPage(ctx context.Context, username string, startPage int) (DiaryEntries, *Pagination, error) {
+	if startPage < 1 {
+		return nil, nil, errors.New("start page must be 1 or greater")
+	}
+	username, err := normalizeUsername(username)
+	if err != nil {
+		return nil, nil, err
+	}
+	items := DiaryEntries{}
+	page := startPage
+	for {
+		entries, pagination, err := u.extractDiaryEntryWithPath(ctx, username, page, DefaultDiaryOpts())
+		if err != nil {
+			return nil, nil, err
+		}
+		items = append(items, entries...)
+		if pagination.IsLast || page >= pagination.TotalPages {
+			return items, pagination, nil
+		}
+		page++
+	}
+}
+
+// DiaryPage returns a single page of diary entries for a given user, along with
+// the pagination for that page. Useful for loading pages on demand, instead of
+// streaming the whole diary
+func (u *UserServiceOp) DiaryPage(ctx context.Context, username string, page int) (DiaryEntries, *Pagination, error) {
+	if page < 1 {
+		return nil, nil, errors.New("page must be 1 or greater")
+	}
+	username, err := normalizeUsername(username)
+	if err != nil {
+		return nil, nil, err
+	}
+	return u.extractDiaryEntryWithPath(ctx, username, page, DefaultDiaryOpts())
+}
+
+// WatchedSince returns the unique films a user has logged in their diary on or
+// after since. The diary is newest first, so pages are fetched one at a time
+// until an entry from before since shows up, or the diary runs out
+func (u *UserServiceOp) WatchedSince(ctx context.Context, username string, since time.Time) (FilmSet, error) {
+	username, err := normalizeUsername(username)
+	if err != nil {
+		return nil, err
+	}
+	films := FilmSet{}
+	seen := map[string]struct{}{}
+	for page := 1; ; page++ {
+		entries, pagination, err := u.extractDiaryEntryWithPath(ctx, username, page, DefaultDiaryOpts())
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if entry.Watched == nil || entry.Slug == nil {
+				continue
+			}
+			if entry.Watched.Before(since) {
+				return films, nil
+			}
+			if _, ok := seen[*entry.Slug]; ok {
+				continue
+			}
+			seen[*entry.Slug] = struct{}{}
+			film := entry.Film
+			if film == nil {
+				film = &Film{Slug: *entry.Slug}
+			}
+			films = append(films, film)
+		}
+		if pagination.IsLast || page >= pagination.TotalPages {
+			return films, nil
+		}
+	}
+}
+
+// StreamDiary streams a users diary in to the given channels. Entries are only
+// sent once, even if a new log shifts a viewing on to another page mid stream
 func (u *UserServiceOp) StreamDiary(ctx context.Context, username string, dec chan *DiaryEntry, done chan error) {
-	var err error
+	u.StreamDiaryWithOpts(ctx, username, DefaultDiaryOpts(), dec, done)
+}
+
+// StreamDiaryWithOpts is StreamDiary, with control over what else is fetched
+// for each entry
+func (u *UserServiceOp) StreamDiaryWithOpts(ctx context.Context, username string, opts DiaryOpts, dec chan *DiaryEntry, done chan error) {
 	var pagination *Pagination
-	defer func() {
-		done <- nil
-	}()
+	username, err := normalizeUsername(username)
+	if err != nil {
+		done <- err
+		return
+	}
+	var seenMu sync.Mutex
+	seen := map[string]struct{}{}
+	send := func(entry *DiaryEntry) {
+		if entry.ViewingID != "" {
+			seenMu.Lock()
+			_, dupe := seen[entry.ViewingID]
+			seen[entry.ViewingID] = struct{}{}
+			seenMu.Unlock()
+			if dupe {
+				return
+			}
+		}
+		dec <- entry
+	}
 
 	// Get the first page. This seeds the pagination.
-	firstEntries, pagination, err := u.extractDiaryEntryWithPath(username, 1)
-	// firstEntries, pagination, err := u.client.User.extractDiaryEntryWithPath(ctx, fmt.Sprintf("%s/%s/films/page/1", u.client.BaseURL, userID))
+	firstEntries, pagination, err := u.extractDiaryEntryWithPath(ctx, username, 1, opts)
 	if err != nil {
 		done <- err
+		return
 	}
 	for _, i := range firstEntries {
-		dec <- i
+		send(i)
 	}
 
-	itemsPerFullPage := len(firstEntries)
-	pagination.TotalItems = itemsPerFullPage
-
+	var lastCount int
 	// If more than 1 page, get the last page too, which will likely be a
 	// partial batch of films
 	if pagination.TotalPages > 1 {
 		var lastEntries DiaryEntries
-		lastEntries, _, err = u.extractDiaryEntryWithPath(username, pagination.TotalPages)
+		lastEntries, _, err = u.extractDiaryEntryWithPath(ctx, username, pagination.TotalPages, opts)
 		if err != nil {
 			done <- err
+			return
 		}
-		pagination.TotalItems += len(lastEntries)
+		lastCount = len(lastEntries)
 		for _, film := range lastEntries {
-			dec <- film
+			send(film)
 		}
 	}
+	pagination.setTotalItemsWithPages(len(firstEntries), lastCount)
 	// Gather up the middle pages here
 	if pagination.TotalPages > 2 {
-		pagination.TotalItems += ((pagination.TotalPages - 2) * itemsPerFullPage)
 		middlePageCount := pagination.TotalPages - 2
 		wg := sync.WaitGroup{}
 		wg.Add(middlePageCount)
 		for i := 2; i < pagination.TotalPages; i++ {
 			go func(i int) {
 				defer wg.Done()
-				pfilms, _, err := u.extractDiaryEntryWithPath(username, i)
+				pfilms, _, err := u.extractDiaryEntryWithPath(ctx, username, i, opts)
 				if err != nil {
 					return
 				}
 				for _, film := range pfilms {
-					dec <- film
+					send(film)
 				}
 			}(i)
 		}
 		wg.Wait()
 	}
+	done <- nil
+}
+
+// LikedLists returns the lists a user has liked, most recently liked first.
+// Stops after maxPages pages
+func (u *UserServiceOp) LikedLists(ctx context.Context, username string) ([]*List, error) {
+	username, err := normalizeUsername(username)
+	if err != nil {
+		return nil, err
+	}
+	lists := []*List{}
+	for page := 1; page <= maxPages; page++ {
+		req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/%s/likes/lists/page/%d/", u.client.baseURL, username, page), nil)
+		if err != nil {
+			return nil, err
+		}
+		items, resp, err := u.client.sendRequest(req, ExtractFilmLists)
+		if err != nil {
+			return nil, err
+		}
+		if resp.Response != nil {
+			dclose(resp.Body)
+		}
+		lists = append(lists, items.Data.([]*List)...)
+		if items.Pagination.IsLast {
+			break
+		}
+	}
+	return lists, nil
 }
 
 // Profile returns a bunch of information about a given user
 func (u *UserServiceOp) Profile(ctx context.Context, userID string) (*User, *Response, error) {
+	userID, err := normalizeUsername(userID)
+	if err != nil {
+		return nil, nil, err
+	}
 	req := mustNewGetRequest(fmt.Sprintf("%s/%s", u.client.baseURL, userID))
 	user, resp, err := u.client.sendRequest(req, ExtractUser)
 	if err != nil {
@@ -212,6 +610,22 @@ func (u *UserServiceOp) Profile(ctx context.Context, userID string) (*User, *Res
 	defer dclose(resp.Body)
 
 	userD := user.Data.(*User)
+	userD.AvatarURL = u.client.assetURL(userD.AvatarURL)
+	// Renamed accounts redirect from the old username to the new one. The page
+	// names the account it belongs to, so cached pages are covered too. Only
+	// fall back to where the redirect ended up when it doesn't
+	canonical := userD.Username
+	if canonical == "" && resp.Response != nil {
+		canonical = strings.Split(strings.Trim(resp.Request.URL.Path, "/"), "/")[0]
+	}
+	if canonical != "" && !strings.EqualFold(canonical, userID) {
+		userD.RenamedFrom = userID
+		userID = strings.ToLower(canonical)
+	}
+	// Keep what did parse, under the name that was asked for
+	if userD.Username == "" {
+		userD.Username = userID
+	}
 
 	userD.Following, _, err = u.Following(ctx, userID)
 	if err != nil {
@@ -227,6 +641,10 @@ func (u *UserServiceOp) Profile(ctx context.Context, userID string) (*User, *Res
 }
 
 func (u *UserServiceOp) peopleWithPath(userID, path string) ([]string, *Response, error) {
+	userID, err := normalizeUsername(userID)
+	if err != nil {
+		return nil, nil, err
+	}
 	curP := 1
 	allPeople := []string{}
 
@@ -270,18 +688,228 @@ func (u *UserServiceOp) Following(ctx context.Context, userID string) ([]string,
 	return allPeople, resp, nil
 }
 
+// Mutuals returns the users who both follow, and are followed by the given
+// user, in the order they appear in Following
+func (u *UserServiceOp) Mutuals(ctx context.Context, userID string) ([]string, error) {
+	following, _, err := u.Following(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	followers, _, err := u.Followers(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	followerSet := make(map[string]struct{}, len(followers))
+	for _, follower := range followers {
+		followerSet[follower] = struct{}{}
+	}
+	mutuals := []string{}
+	for _, followed := range following {
+		if _, ok := followerSet[followed]; ok {
+			mutuals = append(mutuals, followed)
+		}
+	}
+	return mutuals, nil
+}
+
+// UnwatchedFromList returns the films in a list that the user has not watched
+// yet, in list order. The watched films and the list are read from their poster
+// grids at the same time, and only the unwatched films are enhanced
+func (u *UserServiceOp) UnwatchedFromList(ctx context.Context, userID string, list *ListID) (FilmSet, error) {
+	if list == nil {
+		return nil, errors.New("list is required")
+	}
+	userID, err := normalizeUsername(userID)
+	if err != nil {
+		return nil, err
+	}
+	listUser, err := normalizeUsername(list.User)
+	if err != nil {
+		return nil, err
+	}
+	// Stop reading the list if the watched films fail
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var listFilms FilmSet
+	listErrC := make(chan error, 1)
+	go func() {
+		var err error
+		listFilms, err = u.client.gridFilms(ctx, func(page int) string {
+			return fmt.Sprintf("%s/%s/list/%s/page/%v/", u.client.baseURL, listUser, list.Slug, page)
+		})
+		listErrC <- err
+	}()
+	watched, err := u.client.gridFilms(ctx, func(page int) string {
+		return fmt.Sprintf("%s/%s/films/page/%v/", u.client.baseURL, userID, page)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := <-listErrC; err != nil {
+		return nil, err
+	}
+
+	watchedBySlug := watched.BySlug()
+	unwatched := FilmSet{}
+	for _, film := range listFilms {
+		if _, ok := watchedBySlug[film.Slug]; !ok {
+			unwatched = append(unwatched, film)
+		}
+	}
+	if err := u.client.Film.EnhanceFilmList(ctx, &unwatched); err != nil {
+		return nil, err
+	}
+	return unwatched, nil
+}
+
+// NetworkCounts returns how many people a user is following and followed by,
+// using only the first network page. This is handy for showing progress without
+// exhausting Following or Followers first
+func (u *UserServiceOp) NetworkCounts(ctx context.Context, userID string) (*NetworkCounts, error) {
+	userID, err := normalizeUsername(userID)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/%s/followers/", u.client.baseURL, userID), nil)
+	if err != nil {
+		return nil, err
+	}
+	items, resp, err := u.client.sendRequest(req, ExtractNetworkCounts)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Response != nil {
+		dclose(resp.Body)
+	}
+	counts, ok := items.Data.(*NetworkCounts)
+	if !ok {
+		return nil, errors.New("unexpected data type for network counts")
+	}
+	return counts, nil
+}
+
+var networkCountRegex = regexp.MustCompile(`^[0-9,]+`)
+
+// ExtractNetworkCounts returns the NetworkCounts from the header of a
+// following or followers page
+func ExtractNetworkCounts(r io.Reader) (interface{}, *Pagination, error) {
+	doc, err := newDocumentFromReader(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	counts := &NetworkCounts{}
+	doc.Find("ul.sub-nav a").Each(func(i int, s *goquery.Selection) {
+		count, err := strconv.Atoi(strings.ReplaceAll(networkCountRegex.FindString(s.AttrOr("title", "")), ",", ""))
+		if err != nil {
+			return
+		}
+		href := s.AttrOr("href", "")
+		switch {
+		case strings.HasSuffix(href, "/following/"):
+			counts.Following = count
+		case strings.HasSuffix(href, "/followers/"):
+			counts.Followers = count
+		}
+	})
+	return counts, nil, nil
+}
+
 // Exists returns a boolion on if a user exists
 func (u *UserServiceOp) Exists(ctx context.Context, userID string) (bool, error) {
 	return false, nil
 }
 
-// WatchList returns a given users watchlist
+// WatchList returns a given users watchlist, newest additions first
 func (u *UserServiceOp) WatchList(ctx context.Context, userID string) (FilmSet, *Response, error) {
+	return u.WatchListBy(ctx, userID, "")
+}
+
+// WatchListCount returns how many films are in a users watchlist, using the
+// "N films" total in the header of the first watchlist page
+func (u *UserServiceOp) WatchListCount(ctx context.Context, username string) (int, error) {
+	username, err := normalizeUsername(username)
+	if err != nil {
+		return 0, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/%s/watchlist/", u.client.baseURL, username), nil)
+	if err != nil {
+		return 0, err
+	}
+	items, resp, err := u.client.sendRequest(req, ExtractWatchListCount)
+	if err != nil {
+		return 0, err
+	}
+	if resp.Response != nil {
+		dclose(resp.Body)
+	}
+	count, ok := items.Data.(int)
+	if !ok {
+		return 0, errors.New("unexpected data type for watchlist count")
+	}
+	return count, nil
+}
+
+// ExtractWatchListCount returns the number of films in a watchlist from the
+// header of a watchlist page
+func ExtractWatchListCount(r io.Reader) (interface{}, *Pagination, error) {
+	doc, err := newDocumentFromReader(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	return countWithText(doc.Find("span.watchlist-count").First().Text()), nil, nil
+}
+
+// WatchlistProgress returns how many of the films in a users watchlist they
+// have already watched, and how many films are in the watchlist. Films are
+// matched by slug from the poster grids, so no film pages are fetched
+func (u *UserServiceOp) WatchlistProgress(ctx context.Context, username string) (watched, total int, err error) {
+	username, err = normalizeUsername(username)
+	if err != nil {
+		return 0, 0, err
+	}
+	total, err = u.WatchListCount(ctx, username)
+	if err != nil {
+		return 0, 0, err
+	}
+	watchlist, err := u.client.gridFilms(ctx, func(page int) string {
+		return fmt.Sprintf("%s/%s/watchlist/page/%v/", u.client.baseURL, username, page)
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	seen, err := u.client.gridFilms(ctx, func(page int) string {
+		return fmt.Sprintf("%s/%s/films/page/%v/", u.client.baseURL, username, page)
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	seenBySlug := seen.BySlug()
+	for slug := range watchlist.BySlug() {
+		if _, ok := seenBySlug[slug]; ok {
+			watched++
+		}
+	}
+	return watched, total, nil
+}
+
+// WatchListBy returns a given users watchlist in the order given by sortBy,
+// which is the part of the sorted URL after 'by/', like 'release' or
+// 'rating-lowest'. Each film has its WatchlistPosition set from that order
+func (u *UserServiceOp) WatchListBy(ctx context.Context, userID, sortBy string) (FilmSet, *Response, error) {
+	userID, err := normalizeUsername(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	path := "watchlist"
+	if sortBy != "" {
+		path = fmt.Sprintf("watchlist/by/%s", url.PathEscape(sortBy))
+	}
 	var previews FilmSet
 	page := 1
 	// TODREW: This can loop forever
 	for {
-		req, err := http.NewRequest("GET", fmt.Sprintf("%s/%s/watchlist/page/%d", u.client.baseURL, userID, page), nil)
+		req, err := http.NewRequest("GET", fmt.Sprintf("%s/%s/%s/page/%d/", u.client.baseURL, userID, path, page), nil)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -300,62 +928,36 @@ func (u *UserServiceOp) WatchList(ctx context.Context, userID string) (FilmSet,
 		}
 		page++
 	}
+	for i, film := range previews {
+		film.WatchlistPosition = i + 1
+	}
 	return previews, nil, nil
 }
 
 // StreamWatched streams a given list of Watched films
 func (u *UserServiceOp) StreamWatched(ctx context.Context, userID string, rchan chan *Film, done chan error) {
-	var pagination *Pagination
-	defer func() {
-		done <- nil
-	}()
-
-	// Get the first page. This seeds the pagination.
-	firstFilms, pagination, err := u.client.Film.ExtractEnhancedFilmsWithPath(ctx, fmt.Sprintf("%s/%s/films/page/1", u.client.baseURL, userID))
+	userID, err := normalizeUsername(userID)
 	if err != nil {
 		done <- err
+		return
 	}
-	for _, film := range firstFilms {
-		rchan <- film
-	}
-
-	itemsPerFullPage := len(firstFilms)
-	pagination.TotalItems = itemsPerFullPage
-
-	// If more than 1 page, get the last page too, which will likely be a
-	// partial batch of films
-	if pagination.TotalPages > 1 {
-		var lastFilms FilmSet
-		lastFilms, _, err = u.client.Film.ExtractEnhancedFilmsWithPath(ctx, fmt.Sprintf("%s/%s/films/page/%v", u.client.baseURL, userID, pagination.TotalPages))
-		if err != nil {
-			done <- err
-		}
-		pagination.TotalItems += len(lastFilms)
-		for _, film := range lastFilms {
-			rchan <- film
-		}
-	}
-	// Gather up the middle pages here
-	u.client.slurpMiddlePages(ctx, userID, pagination, itemsPerFullPage, rchan, "films")
+	u.client.streamPages(ctx, func(page int) string {
+		return fmt.Sprintf("%s/%s/films/page/%v/", u.client.baseURL, userID, page)
+	}, rchan, done)
 }
 
 // ExtractUserFilms returns a list of films from an io.Reader
 func ExtractUserFilms(r io.Reader) (interface{}, *Pagination, error) {
 	var pageBuf bytes.Buffer
 	tee := io.TeeReader(r, &pageBuf)
-	doc, err := goquery.NewDocumentFromReader(tee)
+	doc, err := newDocumentFromReader(tee)
 	if err != nil {
 		return nil, nil, err
 	}
 	previews := previewsWithDoc(doc)
 	pagination, err := ExtractPagination(&pageBuf)
 	if err != nil {
-		pagination = &Pagination{
-			CurrentPage: 1,
-			NextPage:    1,
-			TotalPages:  1,
-			IsLast:      true,
-		}
+		pagination = singlePagePagination()
 	}
 	return previews, pagination, nil
 }
@@ -368,55 +970,14 @@ func (u *UserServiceOp) StreamList(
 	rchan chan *Film,
 	done chan error,
 ) {
-	var err error
-	var pagination *Pagination
-	defer func() {
-		done <- nil
-	}()
-	firstFilms, pagination, err := u.client.Film.ExtractEnhancedFilmsWithPath(ctx, fmt.Sprintf("%s/%s/list/%s/page/1", u.client.baseURL, username, slug))
+	username, err := normalizeUsername(username)
 	if err != nil {
 		done <- err
+		return
 	}
-	for _, film := range firstFilms {
-		rchan <- film
-	}
-
-	itemsPerFullPage := len(firstFilms)
-	pagination.TotalItems = itemsPerFullPage
-
-	// If more than 1 page, get the last page too, which will likely be a
-	// partial batch of films
-	if pagination.TotalPages > 1 {
-		var lastFilms FilmSet
-		lastFilms, _, err = u.client.Film.ExtractEnhancedFilmsWithPath(ctx, fmt.Sprintf("%s/%s/list/%s/page/%v", u.client.baseURL, username, slug, pagination.TotalPages))
-		if err != nil {
-			done <- err
-		}
-		pagination.TotalItems += len(lastFilms)
-		for _, film := range lastFilms {
-			rchan <- film
-		}
-	}
-	// Gather up the middle pages here
-	if pagination.TotalPages > 2 {
-		pagination.TotalItems += ((pagination.TotalPages - 2) * itemsPerFullPage)
-		middlePageCount := pagination.TotalPages - 2
-		wg := sync.WaitGroup{}
-		wg.Add(middlePageCount)
-		for i := 2; i < pagination.TotalPages; i++ {
-			go func(i int) {
-				defer wg.Done()
-				pfilms, _, err := u.client.Film.ExtractEnhancedFilmsWithPath(ctx, fmt.Sprintf("%s/%s/list/%v/page/%v/", u.client.baseURL, username, slug, i))
-				if err != nil {
-					return
-				}
-				for _, film := range pfilms {
-					rchan <- film
-				}
-			}(i)
-		}
-		wg.Wait()
-	}
+	u.client.streamPages(ctx, func(page int) string {
+		return fmt.Sprintf("%s/%s/list/%s/page/%v/", u.client.baseURL, username, slug, page)
+	}, rchan, done)
 }
 
 // StreamWatchList streams a WatchList back to channels
@@ -426,58 +987,43 @@ func (u *UserServiceOp) StreamWatchList(
 	rchan chan *Film,
 	done chan error,
 ) {
-	var err error
-	var pagination *Pagination
-	defer func() {
-		done <- nil
-	}()
-	firstFilms, pagination, err := u.client.Film.ExtractEnhancedFilmsWithPath(ctx, fmt.Sprintf("%s/%s/watchlist/page/1", u.client.baseURL, username))
+	username, err := normalizeUsername(username)
 	if err != nil {
 		done <- err
+		return
 	}
-	for _, film := range firstFilms {
-		rchan <- film
-	}
-
-	itemsPerFullPage := len(firstFilms)
-	pagination.TotalItems = itemsPerFullPage
-
-	// If more than 1 page, get the last page too, which will likely be a
-	// partial batch of films
-	if pagination.TotalPages > 1 {
-		var lastFilms FilmSet
-		lastFilms, _, err = u.client.Film.ExtractEnhancedFilmsWithPath(ctx, fmt.Sprintf("%s/%s/watchlist/page/%v", u.client.baseURL, username, pagination.TotalPages))
-		if err != nil {
-			done <- err
-		}
-		pagination.TotalItems += len(lastFilms)
-		for _, film := range lastFilms {
-			rchan <- film
-		}
-	}
-	// Gather up the middle pages here
-	u.client.slurpMiddlePages(ctx, username, pagination, itemsPerFullPage, rchan, "watchlist")
+	u.client.streamPages(ctx, func(page int) string {
+		return fmt.Sprintf("%s/%s/watchlist/page/%v/", u.client.baseURL, username, page)
+	}, rchan, done)
 }
 
-func (u *UserServiceOp) extractDiaryEntryWithPath(username string, page int) (DiaryEntries, *Pagination, error) {
+func (u *UserServiceOp) extractDiaryEntryWithPath(ctx context.Context, username string, page int, opts DiaryOpts) (DiaryEntries, *Pagination, error) {
 	var pData *PageData
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/%v/films/diary/page/%v/", u.client.baseURL, username, page), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/%v/films/diary/page/%v/", u.client.baseURL, username, page), nil)
 	if err != nil {
 		return nil, nil, err
 	}
 	var resp *Response
-	pData, resp, err = u.client.sendRequest(req, u.ExtractDiaryEntries)
-	defer dclose(resp.Body)
+	pData, resp, err = u.client.sendRequest(req, func(r io.Reader) (interface{}, *Pagination, error) {
+		return u.extractDiaryEntries(ctx, r, opts)
+	})
 	if err != nil {
 		return nil, nil, err
 	}
+	if resp.Response != nil {
+		defer dclose(resp.Body)
+	}
 	entries := pData.Data.(DiaryEntries)
 	return entries, &pData.Pagination, nil
 }
 
+var watchNumberRegex = regexp.MustCompile(`(?i)watched (\d+) times`)
+
 // NewDiaryEntry returns a new DiaryEntry with attributes for a goquery.Selection
 func NewDiaryEntry(s *goquery.Selection) *DiaryEntry {
-	entry := &DiaryEntry{}
+	entry := &DiaryEntry{
+		ViewingID: s.Find("a").AttrOr("data-viewing-id", ""),
+	}
 	// Figure out date watched
 	val, ok := s.Find("a").Attr("data-viewing-date")
 	if ok {
@@ -488,18 +1034,35 @@ func NewDiaryEntry(s *goquery.Selection) *DiaryEntry {
 			entry.Watched = &t
 		}
 	}
+	if entry.Watched == nil {
+		entry.warnings = append(entry.warnings, "no viewing date found")
+	}
 	// Was it a specified date?
 	sDateS := s.Find("a").AttrOr("data-specified-date", "")
 	if sDateS == "true" {
 		entry.SpecifiedDate = true
 	}
-	// Figure out the rating
-	val, ok = s.Find("a").Attr("data-rating")
-	if ok {
-		rating, err := strconv.Atoi(val)
-		if err == nil {
-			entry.Rating = &rating
+	// Likes live on the diary row, not the edit link
+	row := s.Closest("tr.diary-entry-row")
+
+	// Figure out the rating. Fall back to the visible stars when the edit link
+	// does not carry the rating
+	if val, ok = s.Find("a").Attr("data-rating"); ok {
+		var rating Rating
+		if err := rating.FromAttr(val); err == nil {
+			entry.Rating = rating.ptr()
 		}
+	} else {
+		entry.Rating = ratingWithSelection(row.Find("td.td-rating").Find("span.rating"))
+	}
+	if row.Find("span.icon-liked").Length() > 0 {
+		entry.Liked = true
+	}
+
+	// Likes on the review itself are separate from liking the film
+	countS := row.Find("td.td-review").Find(`[data-likeable-uid^="viewing:"]`).AttrOr("data-count", "")
+	if count, err := strconv.Atoi(strings.ReplaceAll(countS, ",", "")); err == nil {
+		entry.ReviewLikeCount = count
 	}
 
 	// Figure out if a date was a rewatch
@@ -510,6 +1073,15 @@ func NewDiaryEntry(s *goquery.Selection) *DiaryEntry {
 		}
 	}
 
+	// Rewatches may say how many times the film has been watched. Without
+	// that, only a first watch is known for sure
+	rewatchCell := row.Find("td.td-rewatch")
+	if m := watchNumberRegex.FindStringSubmatch(rewatchCell.Find("[title]").AttrOr("title", "")); m != nil {
+		entry.WatchNumber, _ = strconv.Atoi(m[1])
+	} else if !entry.Rewatch && rewatchCell.HasClass("icon-status-off") {
+		entry.WatchNumber = 1
+	}
+
 	// Figure out the title slug
 	val, ok = s.Find("a").Attr("data-film-poster")
 	if ok {
@@ -518,20 +1090,37 @@ func NewDiaryEntry(s *goquery.Selection) *DiaryEntry {
 			entry.Slug = &parts[2]
 		}
 	}
+	if entry.Slug == nil {
+		entry.warnings = append(entry.warnings, "no film slug found")
+	}
+
+	// Entries with a review link to it, like /someguy/film/cure/
+	entry.reviewPath = row.Find("td.td-review a.icon-review").AttrOr("href", "")
 
 	return entry
 }
 
-func (u *UserServiceOp) diaryEntriesWithDoc(doc *goquery.Document) DiaryEntries {
+func (u *UserServiceOp) diaryEntriesWithDoc(ctx context.Context, doc *goquery.Document, opts DiaryOpts) DiaryEntries {
 	entries := DiaryEntries{}
 	var err error
 	doc.Find(".diary-entry-edit").Each(func(i int, s *goquery.Selection) {
 		entry := NewDiaryEntry(s)
 
 		// This one is a little harder to fetch
-		entry.Film, err = u.client.Film.Get(context.TODO(), *entry.Slug)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "error looking up film: %v", err)
+		if opts.IncludeFilms && entry.Slug != nil {
+			entry.Film, err = u.client.Film.Get(ctx, *entry.Slug)
+			if err != nil {
+				u.client.warnf("error looking up film %v: %v", *entry.Slug, err)
+			}
+		} else if entry.Slug != nil {
+			entry.Film = diaryFilmWithSelection(s, *entry.Slug)
+		}
+
+		if opts.IncludeReviews && entry.reviewPath != "" {
+			entry.Review, err = u.client.Review.Get(ctx, entry.reviewPath)
+			if err != nil {
+				u.client.warnf("error looking up review %v: %v", entry.reviewPath, err)
+			}
 		}
 
 		entries = append(entries, entry)
@@ -539,9 +1128,27 @@ func (u *UserServiceOp) diaryEntriesWithDoc(doc *goquery.Document) DiaryEntries
 	return entries
 }
 
-// ExtractDiaryEntries returns a list of DiaryEntries
+// diaryFilmWithSelection returns the partial film the diary entry edit link
+// describes, for when the full film page isn't fetched
+func diaryFilmWithSelection(s *goquery.Selection, slug string) *Film {
+	a := s.Find("a")
+	film := &Film{
+		ID:    a.AttrOr("data-film-id", ""),
+		Title: a.AttrOr("data-film-name", ""),
+		Slug:  slug,
+	}
+	film.Year, _ = strconv.Atoi(a.AttrOr("data-film-year", ""))
+	return film
+}
+
+// ExtractDiaryEntries returns a list of DiaryEntries, looking up the full film
+// for each entry
 func (u *UserServiceOp) ExtractDiaryEntries(r io.Reader) (interface{}, *Pagination, error) {
-	doc, err := goquery.NewDocumentFromReader(r)
+	return u.extractDiaryEntries(context.TODO(), r, DefaultDiaryOpts())
+}
+
+func (u *UserServiceOp) extractDiaryEntries(ctx context.Context, r io.Reader, opts DiaryOpts) (interface{}, *Pagination, error) {
+	doc, err := newDocumentFromReader(r)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -549,10 +1156,34 @@ func (u *UserServiceOp) ExtractDiaryEntries(r io.Reader) (interface{}, *Paginati
 	if err != nil {
 		return nil, nil, err
 	}
-	entries := u.diaryEntriesWithDoc(doc)
+	if count := diaryCountWithDoc(doc); count > 0 {
+		pagination.ItemsPerPage = diaryItemsPerPage
+		pagination.TotalItems = count
+		if pages := (count + diaryItemsPerPage - 1) / diaryItemsPerPage; pages > pagination.TotalPages {
+			pagination.TotalPages = pages
+		}
+	}
+	entries := u.diaryEntriesWithDoc(ctx, doc, opts)
 	return entries, pagination, nil
 }
 
+// diaryItemsPerPage is how many entries a full diary page shows
+const diaryItemsPerPage = 50
+
+// diaryCountWithDoc returns the number of diary entries from the Diary tab of
+// a diary page, like '175&nbsp;films'. Returns 0 if it isn't shown
+func diaryCountWithDoc(doc *goquery.Document) int {
+	var count int
+	doc.Find("ul.sub-nav a").EachWithBreak(func(i int, s *goquery.Selection) bool {
+		if !strings.HasSuffix(s.AttrOr("href", ""), "/films/diary/") {
+			return true
+		}
+		count = countWithText(s.AttrOr("title", ""))
+		return false
+	})
+	return count
+}
+
 // SlurpDiary is just a helper to quickly read in all Diary streams
 func SlurpDiary(itemC chan *DiaryEntry, doneC chan error) (DiaryEntries, error) {
 	var ret DiaryEntries
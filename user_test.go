@@ -3,8 +3,14 @@ package letterboxd
 import (
 	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
 	"os"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -36,6 +42,21 @@ func TestExtractPeople(t *testing.T) {
 	require.Equal(t, false, pagination.IsLast)
 }
 
+func TestExtractNetworkCounts(t *testing.T) {
+	f, err := os.Open("testdata/user/following/1.html")
+	require.NoError(t, err)
+	defer f.Close()
+	items, _, err := ExtractNetworkCounts(f)
+	require.NoError(t, err)
+	require.Equal(t, &NetworkCounts{Following: 37, Followers: 21}, items.(*NetworkCounts))
+}
+
+func TestNetworkCounts(t *testing.T) {
+	got, err := sc.User.NetworkCounts(context.TODO(), "someguy")
+	require.NoError(t, err)
+	require.Equal(t, &NetworkCounts{Following: 29, Followers: 48}, got)
+}
+
 func TestExtractPeopleWithBytes(t *testing.T) {
 	b, err := os.ReadFile("testdata/user/following/1.html")
 	require.NoError(t, err)
@@ -72,9 +93,88 @@ func TestExtractUser(t *testing.T) {
 	require.IsType(t, &User{}, user)
 	u := user.(*User)
 	require.Equal(t, "dankmccoy", u.Username)
+	require.Equal(t, []string{"http://www.flophousepodcast.com", "https://twitter.com/dankmccoy"}, u.Links)
+	require.Equal(t, []SocialAccount{{Network: SocialNetworkTwitter, Handle: "dankmccoy", URL: "https://twitter.com/dankmccoy"}}, u.Socials)
+	require.Equal(t, "https://a.ltrbxd.com/resized/avatar/twitter/5/8/2/6/4/shard/http___pbs.twimg.com_profile_images_1484965792315752449_G_8wgG9z-0-220-0-220-crop.jpg?k=a8b5046a21", u.AvatarURL)
 	require.Equal(t, "Former writer for The Daily Show with Jon Stewart (also Trevor Noah). Podcaster -- The Flop House. I watch a lot of trash, but I also care about good stuff, I swear.", u.Bio)
 }
 
+func TestExtractUserPronouns(t *testing.T) {
+	f, err := os.Open("testdata/user/user-pronouns.html")
+	require.NoError(t, err)
+	defer f.Close()
+	user, _, err := ExtractUser(f)
+	require.NoError(t, err)
+	u := user.(*User)
+	require.Equal(t, "Dan McCoy", u.GivenName)
+	require.Equal(t, "he/him", u.Pronouns)
+
+	// Neither is set
+	user, _, err = ExtractUser(strings.NewReader(`<section class="js-profile-header" data-person="someguy">
+<div class="profile-name-wrap"><h1 title="someguy" class="title-1">someguy</h1></div></section>`))
+	require.NoError(t, err)
+	require.Equal(t, "", user.(*User).GivenName)
+	require.Equal(t, "", user.(*User).Pronouns)
+}
+
+func TestProfileRenamed(t *testing.T) {
+	user, _, err := sc.User.Profile(context.TODO(), "renamedguy")
+	require.NoError(t, err)
+	require.Equal(t, "dankmccoy", user.Username)
+	require.Equal(t, "renamedguy", user.RenamedFrom)
+
+	// No redirect, no rename
+	user, _, err = sc.User.Profile(context.TODO(), "dankmccoy")
+	require.NoError(t, err)
+	require.Equal(t, "", user.RenamedFrom)
+
+	// Without the redirect, like a page from the cache, the page still names
+	// the account it belongs to
+	user, _, err = sc.User.Profile(context.TODO(), "staleguy")
+	require.NoError(t, err)
+	require.Equal(t, "dankmccoy", user.Username)
+	require.Equal(t, "staleguy", user.RenamedFrom)
+}
+
+func TestProfileMissingUsername(t *testing.T) {
+	user, _, err := sc.User.Profile(context.TODO(), "noperson")
+	require.NoError(t, err)
+	require.Equal(t, "noperson", user.Username)
+	require.Equal(t, "Former writer for The Daily Show with Jon Stewart (also Trevor Noah). Podcaster -- The Flop House. I watch a lot of trash, but I also care about good stuff, I swear.", user.Bio)
+	require.Greater(t, user.WatchedFilmCount, 0)
+	require.Equal(t, []string{"no username found"}, user.ParseWarnings)
+
+	// Strict extraction still fails
+	strict := New(WithNoCache(), WithBaseURL(srv.URL), WithStrictExtraction(true))
+	_, _, err = strict.User.Profile(context.TODO(), "noperson")
+	require.ErrorIs(t, err, ErrStrictExtraction)
+}
+
+func TestExtractUserBioLinks(t *testing.T) {
+	user, _, err := ExtractUser(strings.NewReader(`<section class="js-profile-header" data-person="someguy"></section>
+<section id="person-bio"><div class="collapsible-text"><p>Also on <a href="https://www.instagram.com/someguy/">instagram</a> and <a href="https://www.tiktok.com/@someguy">tiktok</a>, see <a href="/someguy/lists/">my lists</a></p></div></section>`))
+	require.NoError(t, err)
+	u := user.(*User)
+	require.Equal(t, []string{"https://www.instagram.com/someguy/", "https://www.tiktok.com/@someguy"}, u.Links)
+	require.Equal(t, []SocialAccount{
+		{Network: SocialNetworkInstagram, Handle: "someguy", URL: "https://www.instagram.com/someguy/"},
+		{Network: SocialNetworkTikTok, Handle: "someguy", URL: "https://www.tiktok.com/@someguy"},
+	}, u.Socials)
+
+	// No links at all
+	user, _, err = ExtractUser(strings.NewReader(`<section class="js-profile-header" data-person="someguy"></section>`))
+	require.NoError(t, err)
+	require.Empty(t, user.(*User).Links)
+	require.Empty(t, user.(*User).Socials)
+}
+
+func TestSocialAccountWithURL(t *testing.T) {
+	require.Equal(t, &SocialAccount{Network: SocialNetworkTwitter, Handle: "someguy", URL: "https://x.com/someguy"}, socialAccountWithURL("https://x.com/someguy"))
+	require.Equal(t, &SocialAccount{Network: SocialNetworkYouTube, Handle: "someguy", URL: "https://youtube.com/@someguy/videos"}, socialAccountWithURL("https://youtube.com/@someguy/videos"))
+	require.Nil(t, socialAccountWithURL("https://twitter.com/"))
+	require.Nil(t, socialAccountWithURL("https://www.flophousepodcast.com"))
+}
+
 func TestUserProfile(t *testing.T) {
 	item, _, err := sc.User.Profile(context.TODO(), "someguy")
 	require.NoError(t, err)
@@ -82,6 +182,18 @@ func TestUserProfile(t *testing.T) {
 	require.Equal(t, 1398, item.WatchedFilmCount)
 }
 
+func TestUserProfileInvalidUsername(t *testing.T) {
+	_, _, err := sc.User.Profile(context.TODO(), "foo/bar")
+	require.ErrorIs(t, err, ErrInvalidUsername)
+
+	item, _, err := sc.User.Profile(context.TODO(), " SomeGuy ")
+	require.NoError(t, err)
+	require.Equal(t, 1398, item.WatchedFilmCount)
+
+	_, err = sc.User.Diary(context.TODO(), "../someguy")
+	require.ErrorIs(t, err, ErrInvalidUsername)
+}
+
 func TestUserFollowing(t *testing.T) {
 	item, _, err := sc.User.Following(context.TODO(), "someguy")
 	require.NoError(t, err)
@@ -138,6 +250,130 @@ func TestStreamListWithChan(t *testing.T) {
 	require.Equal(t, 250, len(watched))
 }
 
+// streamPageTests are the paged film grids streamed with streamPages
+var streamPageTests = map[string]struct {
+	stream func(*Client, chan *Film, chan error)
+	path   string
+	films  int
+	pages  int
+}{
+	"watched": {
+		stream: func(c *Client, filmC chan *Film, done chan error) {
+			c.User.StreamWatched(context.TODO(), "someguy", filmC, done)
+		},
+		path:  "/someguy/films/page/",
+		films: 321,
+		pages: 5,
+	},
+	"list": {
+		stream: func(c *Client, filmC chan *Film, done chan error) {
+			c.User.StreamList(context.TODO(), "dave", "official-top-250-narrative-feature-films", filmC, done)
+		},
+		path:  "/dave/list/official-top-250-narrative-feature-films/page/",
+		films: 250,
+		pages: 3,
+	},
+	"watchlist": {
+		stream: func(c *Client, filmC chan *Film, done chan error) {
+			c.User.StreamWatchList(context.TODO(), "someguy", filmC, done)
+		},
+		path:  "/someguy/watchlist/page/",
+		films: 84,
+		pages: 3,
+	},
+}
+
+func TestStreamPages(t *testing.T) {
+	for name, tt := range streamPageTests {
+		tr := &countingTransport{prefix: tt.path}
+		c := New(WithNoCache(), WithBaseURL(srv.URL))
+		c.client.Transport = tr
+		filmC := make(chan *Film)
+		done := make(chan error)
+		go tt.stream(c, filmC, done)
+		films, err := SlurpFilms(filmC, done)
+		require.NoError(t, err, name)
+		require.Equal(t, tt.films, len(films), name)
+		// Every page is fetched exactly once
+		require.Equal(t, tt.pages, tr.count, name)
+	}
+}
+
+func TestStreamPageCacheKeys(t *testing.T) {
+	for name, tt := range streamPageTests {
+		tr := &countingTransport{prefix: tt.path}
+		c := New(WithNoCache(), WithBaseURL(srv.URL))
+		c.client.Transport = tr
+		filmC := make(chan *Film)
+		done := make(chan error)
+		go tt.stream(c, filmC, done)
+		_, err := SlurpFilms(filmC, done)
+		require.NoError(t, err, name)
+		require.Equal(t, tt.pages, len(tr.urls), name)
+		// Middle pages are keyed the same way as the first page
+		for _, u := range tr.urls {
+			page := strings.TrimSuffix(strings.TrimPrefix(u.Path, tt.path), "/")
+			require.Equal(t, fmt.Sprintf("/letterboxd/fullpage%s%s/", tt.path, page), pageCacheKey(u), name)
+		}
+	}
+}
+
+func TestDiaryWithOptsNoFilms(t *testing.T) {
+	tr := &countingTransport{prefix: "/film/"}
+	c := New(WithNoCache(), WithBaseURL(srv.URL))
+	c.client.Transport = tr
+	items, err := c.User.DiaryWithOpts(context.TODO(), "someguy", DiaryOpts{})
+	require.NoError(t, err)
+	require.Equal(t, 175, len(items))
+	require.Equal(t, 0, tr.count)
+	// The partial film still comes from the diary page
+	for _, item := range items {
+		require.NotNil(t, item.Film)
+		require.Equal(t, *item.Slug, item.Film.Slug)
+		require.NotEmpty(t, item.Film.Title)
+		require.Nil(t, item.Review)
+	}
+}
+
+func TestDiaryWithOptsReviews(t *testing.T) {
+	items, err := sc.User.DiaryWithOpts(context.TODO(), "reviewguy", DiaryOpts{IncludeReviews: true})
+	require.NoError(t, err)
+	require.Equal(t, 2, len(items))
+	require.Equal(t, "cure", items[0].Film.Slug)
+	require.Equal(t, 1997, items[0].Film.Year)
+	require.NotNil(t, items[0].Review)
+	require.Equal(t, "300839528", items[0].Review.ViewingID)
+	require.Equal(t, "pstinnett", items[0].Review.Username)
+	require.Equal(t, 1024, items[0].Review.Likes)
+	require.True(t, strings.HasPrefix(items[0].Review.Text, "The slowest, quietest dread."))
+	require.Nil(t, items[1].Review)
+}
+
+// failingTransport fails the requests made for paths starting with prefix
+type failingTransport struct {
+	prefix string
+}
+
+func (f *failingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if strings.HasPrefix(r.URL.Path, f.prefix) {
+		return nil, errors.New("lookup failed")
+	}
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestDiaryWithOptsLookupWarnings(t *testing.T) {
+	var warnings bytes.Buffer
+	c := New(WithNoCache(), WithBaseURL(srv.URL), WithWarnWriter(&warnings))
+	c.client.Transport = &failingTransport{prefix: "/pstinnett/film/"}
+
+	items, err := c.User.DiaryWithOpts(context.TODO(), "reviewguy", DiaryOpts{IncludeReviews: true})
+	require.NoError(t, err)
+	require.Equal(t, 2, len(items))
+	require.Nil(t, items[0].Review)
+	require.True(t, strings.HasPrefix(warnings.String(), "error looking up review /pstinnett/film/cure/: "))
+	require.True(t, strings.HasSuffix(warnings.String(), "\n"))
+}
+
 func TestStreamDiaryWithChan(t *testing.T) {
 	diaryC := make(chan *DiaryEntry)
 	doneC := make(chan error)
@@ -148,8 +384,286 @@ func TestStreamDiaryWithChan(t *testing.T) {
 	require.Equal(t, 175, len(items))
 }
 
+func TestExtractDiaryEntriesPagination(t *testing.T) {
+	f, err := os.Open("testdata/user/diary-paginated/1.html")
+	require.NoError(t, err)
+	defer f.Close()
+	items, pagination, err := sc.User.(*UserServiceOp).ExtractDiaryEntries(f)
+	require.NoError(t, err)
+	require.Equal(t, 50, len(items.(DiaryEntries)))
+	require.Equal(t, 175, pagination.TotalItems)
+	require.Equal(t, 4, pagination.TotalPages)
+	require.Equal(t, 50, pagination.ItemsPerPage)
+
+	// No diary count to seed from
+	f, err = os.Open("testdata/user/diary-overlap/1.html")
+	require.NoError(t, err)
+	defer f.Close()
+	_, pagination, err = sc.User.(*UserServiceOp).ExtractDiaryEntries(f)
+	require.NoError(t, err)
+	require.Equal(t, 0, pagination.TotalItems)
+}
+
+func TestDiaryFilmAverageRating(t *testing.T) {
+	entries, _, err := sc.User.DiaryPage(context.TODO(), "someguy", 1)
+	require.NoError(t, err)
+	require.NotEmpty(t, entries)
+	for _, entry := range entries {
+		require.NotNil(t, entry.Film)
+		require.Equal(t, 3.21, entry.Film.AverageRating)
+		require.Equal(t, 5914, entry.Film.RatingCount)
+	}
+}
+
+func TestStreamDiaryOverlap(t *testing.T) {
+	diaryC := make(chan *DiaryEntry)
+	doneC := make(chan error)
+	go sc.User.StreamDiary(context.TODO(), "overlapguy", diaryC, doneC)
+	items, err := SlurpDiary(diaryC, doneC)
+	require.NoError(t, err)
+	// 300923039 is on both pages
+	require.Equal(t, 3, len(items))
+	ids := map[string]int{}
+	for _, item := range items {
+		ids[item.ViewingID]++
+	}
+	require.Equal(t, map[string]int{"300839528": 1, "300923039": 1, "299104412": 1}, ids)
+}
+
 func TestGetDiary(t *testing.T) {
 	items, err := sc.User.Diary(context.Background(), "someguy")
 	require.NoError(t, err)
 	require.Equal(t, 175, len(items))
 }
+
+func TestExtractRatingDistribution(t *testing.T) {
+	f, err := os.Open("testdata/user/user.html")
+	require.NoError(t, err)
+	defer f.Close()
+	items, _, err := ExtractRatingDistribution(f)
+	require.NoError(t, err)
+	dist := items.(map[float64]int)
+	require.Equal(t, 10, len(dist))
+	require.Equal(t, 27, dist[0.5])
+	require.Equal(t, 21, dist[1])
+	require.Equal(t, 299, dist[3.5])
+	require.Equal(t, 59, dist[5])
+}
+
+func TestUserRatingDistribution(t *testing.T) {
+	dist, err := sc.User.RatingDistribution(context.TODO(), "someguy")
+	require.NoError(t, err)
+	require.Equal(t, 335, dist[3])
+}
+
+func TestExtractRatingsPage(t *testing.T) {
+	f, err := os.Open("testdata/user/ratings.html")
+	require.NoError(t, err)
+	defer f.Close()
+	items, pagination, err := ExtractRatingsPage(f)
+	require.NoError(t, err)
+	dist := items.(map[float64]int)
+	require.Equal(t, 10, len(dist))
+	require.Equal(t, 2, dist[0.5])
+	require.Equal(t, 52, dist[3.5])
+	require.Equal(t, 1012, dist[5])
+	require.Equal(t, 3, pagination.TotalPages)
+}
+
+func TestUserRatingsPage(t *testing.T) {
+	dist, pagination, err := sc.User.RatingsPage(context.TODO(), "someguy")
+	require.NoError(t, err)
+	require.Equal(t, 40, dist[3])
+	require.Equal(t, 1, pagination.CurrentPage)
+
+	_, _, err = sc.User.RatingsPage(context.TODO(), "emptyguy")
+	require.Error(t, err)
+}
+
+func TestRatingWithHistogramSegment(t *testing.T) {
+	tests := map[string]struct {
+		seg     string
+		want    float64
+		wantErr bool
+	}{
+		"half":      {seg: "%C2%BD", want: 0.5},
+		"whole":     {seg: "4", want: 4},
+		"with-half": {seg: "2%C2%BD", want: 2.5},
+		"garbage":   {seg: "nope", wantErr: true},
+		"too-high":  {seg: "6", wantErr: true},
+		"decimal":   {seg: ".5", want: 0.5},
+		"decimal-3": {seg: "3.5", want: 3.5},
+		"not-half":  {seg: "3.2", wantErr: true},
+	}
+	for desc, tt := range tests {
+		got, err := ratingWithHistogramSegment(tt.seg)
+		if tt.wantErr {
+			require.Error(t, err, desc)
+		} else {
+			require.NoError(t, err, desc)
+			require.Equal(t, tt.want, got, desc)
+		}
+	}
+}
+
+func TestDiaryErr(t *testing.T) {
+	_, err := sc.User.Diary(context.Background(), "neverexist")
+	require.Error(t, err)
+}
+
+func TestMustDiaryPanics(t *testing.T) {
+	require.Panics(t, func() {
+		sc.User.MustDiary(context.Background(), "neverexist")
+	})
+}
+
+func TestDiaryFromPage(t *testing.T) {
+	items, pagination, err := sc.User.DiaryFromPage(context.Background(), "someguy", 3)
+	require.NoError(t, err)
+	require.Equal(t, 75, len(items))
+	require.Equal(t, 4, pagination.CurrentPage)
+
+	// Page 1 starts with 'cure', page 3 does not
+	require.Equal(t, "the-long-dumb-road", *items[0].Slug)
+	first, _, err := sc.User.DiaryFromPage(context.Background(), "someguy", 4)
+	require.NoError(t, err)
+	require.Equal(t, *first[0].Slug, *items[50].Slug)
+
+	_, _, err = sc.User.DiaryFromPage(context.Background(), "someguy", 0)
+	require.Error(t, err)
+}
+
+func TestWatchListBy(t *testing.T) {
+	got, _, err := sc.User.WatchListBy(context.TODO(), "someguy", "release")
+	require.NoError(t, err)
+	require.Equal(t, 28, len(got))
+	require.Equal(t, "crimes-of-the-future-2022", got[0].Slug)
+	require.Equal(t, "solaris", got[4].Slug)
+	for i, film := range got {
+		require.Equal(t, i+1, film.WatchlistPosition, film.Slug)
+	}
+
+	// Position survives reordering
+	sort.Slice(got, func(i, j int) bool { return got[i].Slug < got[j].Slug })
+	byPosition := map[int]string{}
+	for _, film := range got {
+		byPosition[film.WatchlistPosition] = film.Slug
+	}
+	require.Equal(t, "solaris", byPosition[5])
+}
+
+func TestDiaryPage(t *testing.T) {
+	items, pagination, err := sc.User.DiaryPage(context.Background(), "someguy", 1)
+	require.NoError(t, err)
+	require.Equal(t, 50, len(items))
+	require.Equal(t, "cure", *items[0].Slug)
+	require.Equal(t, 1, pagination.CurrentPage)
+	require.Equal(t, 4, pagination.TotalPages)
+	require.False(t, pagination.IsLast)
+
+	_, _, err = sc.User.DiaryPage(context.Background(), "someguy", 0)
+	require.Error(t, err)
+}
+
+func TestMutuals(t *testing.T) {
+	got, err := sc.User.Mutuals(context.TODO(), "someguy")
+	require.NoError(t, err)
+	require.Equal(t, []string{"shelton", "andyatmidnight", "cinemafromage"}, got)
+}
+
+func TestUnwatchedFromList(t *testing.T) {
+	tr := &countingTransport{prefix: "/film/"}
+	c := New(WithNoCache(), WithBaseURL(srv.URL))
+	c.client.Transport = tr
+
+	got, err := c.User.UnwatchedFromList(context.TODO(), "singleguy", &ListID{User: "dave", Slug: "official-top-250-narrative-feature-films"})
+	require.NoError(t, err)
+	// 5 of the 250 have been watched
+	require.Equal(t, 245, len(got))
+	// Only the unwatched films are looked up
+	require.Equal(t, 245, tr.count)
+	unwatched := got.BySlug()
+	for _, slug := range []string{"parasite-2019", "casablanca", "the-grand-budapest-hotel", "get-out-2017", "little-women-2019"} {
+		require.NotContains(t, unwatched, slug)
+	}
+	require.Contains(t, unwatched, "come-and-see")
+
+	_, err = c.User.UnwatchedFromList(context.TODO(), "singleguy", nil)
+	require.Error(t, err)
+
+	_, err = sc.User.UnwatchedFromList(context.TODO(), "singleguy", &ListID{User: "nobody", Slug: "not-a-list"})
+	require.Error(t, err)
+}
+
+func TestWatchedSince(t *testing.T) {
+	tr := &countingTransport{prefix: "/someguy/films/diary/page/"}
+	c := New(WithNoCache(), WithBaseURL(srv.URL))
+	c.client.Transport = tr
+
+	// The boundary is a few entries in to page 2
+	since := time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC)
+	films, err := c.User.WatchedSince(context.Background(), "someguy", since)
+	require.NoError(t, err)
+	require.Equal(t, 54, len(films))
+	// Pages 3 and 4 are never needed
+	require.Equal(t, 2, tr.count)
+
+	// Nothing logged after the newest entry
+	films, err = c.User.WatchedSince(context.Background(), "someguy", time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Empty(t, films)
+}
+
+func TestStreamWatchedEmpty(t *testing.T) {
+	tr := &countingTransport{prefix: "/emptyguy/films/"}
+	c := New(WithNoCache(), WithBaseURL(srv.URL))
+	c.client.Transport = tr
+
+	watchedC := make(chan *Film)
+	done := make(chan error)
+	go c.User.StreamWatched(context.TODO(), "emptyguy", watchedC, done)
+	watched, err := SlurpFilms(watchedC, done)
+	require.NoError(t, err)
+	require.Empty(t, watched)
+	require.Equal(t, 1, tr.count)
+}
+
+func TestLikedLists(t *testing.T) {
+	lists, err := sc.User.LikedLists(context.TODO(), "someguy")
+	require.NoError(t, err)
+	require.Equal(t, 3, len(lists))
+	require.Equal(t, &List{
+		Title:      "J-Horror Essentials",
+		Owner:      "karsten",
+		Slug:       "j-horror-essentials",
+		Visibility: ListVisibilityPublic,
+	}, lists[1])
+	require.Equal(t, "sam", lists[2].Owner)
+	require.Equal(t, "detectives", lists[2].Slug)
+
+	_, err = sc.User.LikedLists(context.TODO(), "")
+	require.Error(t, err)
+}
+
+func TestWatchListCount(t *testing.T) {
+	count, err := sc.User.WatchListCount(context.TODO(), "someguy")
+	require.NoError(t, err)
+	require.Equal(t, 80, count)
+}
+
+func TestWatchlistProgress(t *testing.T) {
+	tr := &countingTransport{prefix: "/film/"}
+	c := New(WithNoCache(), WithBaseURL(srv.URL))
+	c.client.Transport = tr
+
+	watched, total, err := c.User.WatchlistProgress(context.TODO(), "progressguy")
+	require.NoError(t, err)
+	require.Equal(t, 4, total)
+	// Cure is in the watchlist twice, but only counted once
+	require.Equal(t, 2, watched)
+	// Only the poster grids are read
+	require.Equal(t, 0, tr.count)
+
+	_, _, err = sc.User.WatchlistProgress(context.TODO(), "")
+	require.Error(t, err)
+}
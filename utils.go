@@ -2,10 +2,13 @@ package letterboxd
 
 import (
 	"errors"
+	"fmt"
 	"io"
 	"math/rand"
 	"net/url"
+	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
@@ -36,6 +39,71 @@ func normalizeSlug(slug string) string {
 	return slug
 }
 
+// ErrInvalidUsername is returned when a username could not possibly be a
+// Letterboxd user, like one containing a slash
+var ErrInvalidUsername = errors.New("invalid username")
+
+// usernameRegex matches a lowercased Letterboxd username
+var usernameRegex = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// normalizeUsername trims and lowercases a username, so it is safe to use in a
+// URL path
+func normalizeUsername(s string) (string, error) {
+	username := strings.ToLower(strings.TrimSpace(s))
+	if !usernameRegex.MatchString(username) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidUsername, s)
+	}
+	return username, nil
+}
+
+// slugRegex matches a bare film slug, like 'everything-everywhere-all-at-once'.
+// Films without a proper slug use their ID instead, like 'film:585006'
+var slugRegex = regexp.MustCompile(`^[a-zA-Z0-9_:-]+$`)
+
+// SlugFromURL returns the film slug from a full Letterboxd URL, a hostname
+// without the scheme, a path, or a bare slug. Anything that is not a film
+// will return an error
+func SlugFromURL(u string) (string, error) {
+	u = strings.TrimSpace(u)
+	isPath := strings.HasPrefix(u, "/")
+	if strings.HasPrefix(u, "letterboxd.com") || strings.HasPrefix(u, "www.letterboxd.com") {
+		u = "https://" + u
+	}
+	if strings.Contains(u, "://") {
+		parsed, err := url.Parse(u)
+		if err != nil {
+			return "", err
+		}
+		if host := parsed.Hostname(); host != "letterboxd.com" && !strings.HasSuffix(host, ".letterboxd.com") {
+			return "", errors.New("not a letterboxd URL")
+		}
+		u = parsed.Path
+		isPath = true
+	}
+	parts := strings.Split(strings.Trim(u, "/"), "/")
+	switch {
+	case len(parts) >= 2 && parts[0] == "film":
+		// /film/slug/
+		u = parts[1]
+	case len(parts) >= 3 && parts[1] == "film":
+		// /username/film/slug/
+		u = parts[2]
+	case len(parts) == 1 && !isPath:
+		u = parts[0]
+	default:
+		return "", fmt.Errorf("not a film URL: %v", u)
+	}
+	if !slugRegex.MatchString(u) {
+		return "", fmt.Errorf("invalid film slug: %v", u)
+	}
+	return u, nil
+}
+
+// PathFromSlug returns the path to a film page from a given slug
+func PathFromSlug(slug string) string {
+	return fmt.Sprintf("/film/%s/", normalizeSlug(slug))
+}
+
 // stringInSlice is a tiny helper to determin if a slice of strings contains a specific string
 func stringInSlice(a string, list []string) bool {
 	for _, b := range list {
@@ -69,13 +137,30 @@ func panicIfErr(err error) {
 	}
 }
 
+// rng is seeded once for the package, instead of on every use. A rand.Rand is
+// not safe for concurrent use, so always go through randIntn
+var (
+	rng   = rand.New(rand.NewSource(time.Now().UnixNano())) // nolint:gosec
+	rngMu sync.Mutex
+)
+
+// randIntn returns a random int in [0,n) from the package rng
+func randIntn(n int) int {
+	rngMu.Lock()
+	defer rngMu.Unlock()
+	return rng.Intn(n)
+}
+
+// randomPage returns a random page number in [1,total]
+func randomPage(total int) int {
+	return randIntn(total) + 1
+}
+
 func populateRemainingPages(count, total int, shuffle bool) []int {
 	var remainingPages []int
 	if shuffle {
-		rand.Seed(time.Now().UnixNano())
 		for i := 0; i <= count; i++ {
-			// We don't care so much about the security of this random number
-			remainingPages = append(remainingPages, rand.Intn(total-2+1)+2) // nolint:golint,gosec
+			remainingPages = append(remainingPages, randIntn(total-2+1)+2)
 		}
 	} else {
 		remainingPages = makeRange(2, count+1)
@@ -83,8 +168,20 @@ func populateRemainingPages(count, total int, shuffle bool) []int {
 	return remainingPages
 }
 
-func mustNewDocumentFromReader(r io.Reader) *goquery.Document {
+// newDocumentFromReader is a wrapper around goquery.NewDocumentFromReader
+// that wraps any read or parse errors, instead of panicking
+func newDocumentFromReader(r io.Reader) (*goquery.Document, error) {
 	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse document: %w", err)
+	}
+	return doc, nil
+}
+
+// mustNewDocumentFromReader is the same as newDocumentFromReader, but panics
+// on error. This should only be used in tests
+func mustNewDocumentFromReader(r io.Reader) *goquery.Document {
+	doc, err := newDocumentFromReader(r)
 	if err != nil {
 		panic(err)
 	}
@@ -100,6 +197,25 @@ func mustParseURL(u string) *url.URL {
 	return url
 }
 
+// absoluteURL resolves raw against base, so protocol-relative
+// ('//a.ltrbxd.com/x.jpg') and relative ('/static/x.jpg') URLs become
+// absolute. Empty and already absolute URLs are returned as is
+func absoluteURL(base, raw string) string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return ""
+	}
+	ref, err := url.Parse(raw)
+	if err != nil || ref.IsAbs() {
+		return raw
+	}
+	b, err := url.Parse(base)
+	if err != nil {
+		return raw
+	}
+	return b.ResolveReference(ref).String()
+}
+
 func min(values ...int) (min int) {
 	if len(values) == 0 {
 		panic("cannot detect a minimum value in an empty slice")
@@ -1,6 +1,8 @@
 package letterboxd
 
 import (
+	"errors"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -99,6 +101,49 @@ func TestPopulateRemainingPagesShuffle(t *testing.T) {
 	require.Equal(t, 6, len(got))
 }
 
+func TestPopulateRemainingPagesShuffleRapid(t *testing.T) {
+	// Calls in a tight loop used to re-seed with near identical values. With
+	// 20 picks out of 999 pages, a collision is vanishingly unlikely
+	first := populateRemainingPages(20, 1000, true)
+	second := populateRemainingPages(20, 1000, true)
+	require.NotEqual(t, first, second)
+	for _, page := range append(first, second...) {
+		require.GreaterOrEqual(t, page, 2)
+		require.LessOrEqual(t, page, 1000)
+	}
+}
+
+func TestRandIntnConcurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	results := make([][]int, 10)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				results[i] = append(results[i], randIntn(5))
+			}
+		}(i)
+	}
+	wg.Wait()
+	for _, got := range results {
+		for _, n := range got {
+			require.GreaterOrEqual(t, n, 0)
+			require.Less(t, n, 5)
+		}
+	}
+}
+
+func TestRandomPage(t *testing.T) {
+	seen := map[int]bool{}
+	for i := 0; i < 1000; i++ {
+		seen[randomPage(3)] = true
+	}
+	// The first page can be picked too
+	require.Equal(t, map[int]bool{1: true, 2: true, 3: true}, seen)
+	require.Equal(t, 1, randomPage(1))
+}
+
 func TestMin(t *testing.T) {
 	got := min(2, 1, 3)
 	require.Equal(t, 1, got)
@@ -117,3 +162,116 @@ func TestStringOr(t *testing.T) {
 	require.Equal(t, "given", stringOr("given", "default"))
 	require.Equal(t, "default", stringOr("", "default"))
 }
+
+// errReader is an io.Reader that always returns an error
+type errReader struct{}
+
+func (errReader) Read(p []byte) (int, error) {
+	return 0, errors.New("read failure")
+}
+
+func TestNewDocumentFromReaderErr(t *testing.T) {
+	doc, err := newDocumentFromReader(errReader{})
+	require.Error(t, err)
+	require.Nil(t, doc)
+
+	require.NotPanics(t, func() {
+		_, _, err = ExtractUserFilms(errReader{})
+	})
+	require.Error(t, err)
+
+	require.NotPanics(t, func() {
+		_, err = ExtractPagination(errReader{})
+	})
+	require.Error(t, err)
+
+	require.Panics(t, func() { mustNewDocumentFromReader(errReader{}) })
+}
+
+func TestSlugFromURL(t *testing.T) {
+	tests := map[string]struct {
+		u       string
+		want    string
+		wantErr bool
+	}{
+		"full-url":          {u: "https://letterboxd.com/film/everything-everywhere-all-at-once/", want: "everything-everywhere-all-at-once"},
+		"www-url":           {u: "https://www.letterboxd.com/film/everything-everywhere-all-at-once", want: "everything-everywhere-all-at-once"},
+		"no-scheme":         {u: "letterboxd.com/film/everything-everywhere-all-at-once/", want: "everything-everywhere-all-at-once"},
+		"sub-page":          {u: "https://letterboxd.com/film/cure/reviews/", want: "cure"},
+		"user-film":         {u: "https://letterboxd.com/someguy/film/cure/", want: "cure"},
+		"path":              {u: "/film/everything-everywhere-all-at-once/", want: "everything-everywhere-all-at-once"},
+		"path-no-slash":     {u: "film/cure", want: "cure"},
+		"bare-slug":         {u: "everything-everywhere-all-at-once", want: "everything-everywhere-all-at-once"},
+		"bare-slug-spaces":  {u: " cure ", want: "cure"},
+		"id-slug":           {u: "/film/film:585006/", want: "film:585006"},
+		"other-host":        {u: "https://www.google.com/film/cure/", wantErr: true},
+		"lookalike-host":    {u: "https://evilletterboxd.com/film/cure/", wantErr: true},
+		"list-url":          {u: "https://letterboxd.com/dave/list/official-top-250-narrative-feature-films/", wantErr: true},
+		"user-path":         {u: "/someguy", wantErr: true},
+		"empty":             {u: "", wantErr: true},
+		"not-a-slug":        {u: "Some Film Title", wantErr: true},
+		"film-without-slug": {u: "https://letterboxd.com/film/", wantErr: true},
+	}
+	for desc, tt := range tests {
+		got, err := SlugFromURL(tt.u)
+		if tt.wantErr {
+			require.Error(t, err, desc)
+		} else {
+			require.NoError(t, err, desc)
+			require.Equal(t, tt.want, got, desc)
+		}
+	}
+}
+
+func TestPathFromSlug(t *testing.T) {
+	require.Equal(t, "/film/cure/", PathFromSlug("cure"))
+	got, err := SlugFromURL(PathFromSlug("cure"))
+	require.NoError(t, err)
+	require.Equal(t, "cure", got)
+}
+
+func TestNormalizeUsername(t *testing.T) {
+	tests := map[string]struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		"plain":      {in: "someguy", want: "someguy"},
+		"upper":      {in: "SomeGuy", want: "someguy"},
+		"padded":     {in: "  dave\n", want: "dave"},
+		"underscore": {in: "some_guy_2", want: "some_guy_2"},
+		"slash":      {in: "foo/bar", wantErr: true},
+		"space":      {in: "foo bar", wantErr: true},
+		"dots":       {in: "..", wantErr: true},
+		"query":      {in: "foo?bar=1", wantErr: true},
+		"empty":      {in: " ", wantErr: true},
+	}
+	for desc, tt := range tests {
+		got, err := normalizeUsername(tt.in)
+		if tt.wantErr {
+			require.ErrorIs(t, err, ErrInvalidUsername, desc)
+		} else {
+			require.NoError(t, err, desc)
+			require.Equal(t, tt.want, got, desc)
+		}
+	}
+}
+
+func TestAbsoluteURL(t *testing.T) {
+	tests := map[string]struct {
+		base string
+		raw  string
+		want string
+	}{
+		"protocol-relative": {base: "https://a.ltrbxd.com", raw: "//a.ltrbxd.com/resized/film-poster/1.jpg", want: "https://a.ltrbxd.com/resized/film-poster/1.jpg"},
+		"relative-path":     {base: "https://a.ltrbxd.com", raw: "/resized/avatar/1.jpg?k=abc", want: "https://a.ltrbxd.com/resized/avatar/1.jpg?k=abc"},
+		"relative-no-slash": {base: "https://a.ltrbxd.com/", raw: "static/img/empty-poster-230.png", want: "https://a.ltrbxd.com/static/img/empty-poster-230.png"},
+		"http-base":         {base: "http://127.0.0.1:8080", raw: "//cdn.example.com/1.jpg", want: "http://cdn.example.com/1.jpg"},
+		"already-absolute":  {base: "https://a.ltrbxd.com", raw: "https://s.ltrbxd.com/static/1.png", want: "https://s.ltrbxd.com/static/1.png"},
+		"empty":             {base: "https://a.ltrbxd.com", raw: "", want: ""},
+		"spaces":            {base: "https://a.ltrbxd.com", raw: " /1.jpg ", want: "https://a.ltrbxd.com/1.jpg"},
+	}
+	for desc, tt := range tests {
+		require.Equal(t, tt.want, absoluteURL(tt.base, tt.raw), desc)
+	}
+}